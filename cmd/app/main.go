@@ -2,22 +2,35 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
 	"github.com/SornchaiTheDev/nisit-scan-backend/internal/adapters/rest"
 	"github.com/SornchaiTheDev/nisit-scan-backend/internal/auth"
 	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/migrations"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/pubsub"
 	repositories "github.com/SornchaiTheDev/nisit-scan-backend/internal/repositories/pgx"
 	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Usage:
+//
+//	app [-migrate]                 apply pending migrations, then serve
+//	app migrate up|down|status|... run a goose command and exit
 func main() {
+	migrate := flag.Bool("migrate", false, "apply pending migrations before starting the server")
+	flag.Parse()
+
 	libs.InitEnv()
 
 	dbUrl := os.Getenv("DATABASE_URL")
@@ -38,39 +51,126 @@ func main() {
 
 	log.Println("Database connected")
 
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" || len(args) < 2 {
+			log.Fatal("Usage: app migrate up|up-by-one|up-to|down|down-to|redo|reset|status|version")
+		}
+
+		if err := migrations.Run(ctx, conn, args[1], args[2:]...); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *migrate {
+		if err := migrations.Run(ctx, conn, "up"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Init sqlc
 	q := sqlc.New(conn)
 
 	// Init repositories
 	adminRepo := repositories.NewAdminRepo(ctx, q)
 	eventRepo := repositories.NewEventRepo(ctx, q)
-	staffRepo := repositories.NewStaffRepository(ctx, q)
-	participantRepo := repositories.NewParticipantRepo(ctx, q)
+	staffRepo := repositories.NewStaffRepository(ctx, conn, q)
+	participantRepo := repositories.NewParticipantRepo(ctx, conn, q)
+	sessionRepo := repositories.NewSessionRepo(ctx, q)
+	studentRepo := repositories.NewStudentRepo(ctx, conn, q)
 	tokenRepo := repositories.NewTokenRepository(ctx, q)
+	auditRepo := repositories.NewAuditRepo(ctx, q)
+	accessRequestRepo := repositories.NewAccessRequestRepo(ctx, q)
+	signingKeyRepo := repositories.NewSigningKeyRepo(ctx, q)
+	apiKeyRepo := repositories.NewApiKeyRepo(ctx, q)
+	registrationRepo := repositories.NewRegistrationRepo(ctx, q)
+	oAuthRepo := repositories.NewOAuthRepo(ctx, q)
+
+	// Init pub/sub, use Postgres when running several instances
+	ps := pubsub.NewMemoryPubSub()
+	if os.Getenv("PUBSUB_DRIVER") == "postgres" {
+		ps = pubsub.NewPostgresPubSub(ctx, conn)
+	}
 
 	// Init Service
-	adminService := services.NewAdminService(adminRepo)
-	eventService := services.NewEventService(eventRepo)
-	staffService := services.NewStaffService(staffRepo)
-	participantService := services.NewParticipantService(participantRepo)
-	tokenService := services.NewTokenService(tokenRepo)
+	auditService := services.NewAuditService(auditRepo)
+	adminService := services.NewAdminService(adminRepo, tokenRepo, auditService)
+	eventService := services.NewEventService(eventRepo, auditService)
+	staffService := services.NewStaffService(staffRepo, tokenRepo, auditService)
+	feedService := services.NewFeedService(ps)
+	participantService := services.NewParticipantService(participantRepo, eventRepo, sessionRepo, registrationRepo, feedService, auditService)
+	tokenService := services.NewTokenService(tokenRepo, adminService, staffService, auditService)
+	sessionService := services.NewSessionService(sessionRepo, auditService)
+	studentService := services.NewStudentService(studentRepo, participantRepo, auditService)
+	accessRequestService := services.NewAccessRequestService(accessRequestRepo, adminService, staffService, auditService)
+	apiKeyService := services.NewApiKeyService(apiKeyRepo, auditService)
+	registrationService := services.NewRegistrationService(registrationRepo, auditService)
+
+	barcodeFormat, err := libs.BarcodeFormatFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	libs.UseBarcodeFormat(barcodeFormat)
+
+	// Init signing keys, rotated every JWT_KEY_ROTATION (30 days by default)
+	keyRotation := 30 * 24 * time.Hour
+	if value := os.Getenv("JWT_KEY_ROTATION"); value != "" {
+		keyRotation, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Invalid JWT_KEY_ROTATION: ", err)
+		}
+	}
+
+	signingKeyService := services.NewSigningKeyService(signingKeyRepo, keyRotation)
+	if err := signingKeyService.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	go signingKeyService.Run(ctx)
 
 	// Init Auth
-	authService := auth.NewGoogleOAuth(adminService, staffService)
+	providers := []auth.Provider{auth.NewGoogleProvider()}
+	if oidcConfig := auth.OIDCConfigFromEnv(); oidcConfig != nil {
+		providers = append(providers, auth.NewOIDCProvider(*oidcConfig))
+	}
+
+	middleware.UseTokenService(tokenService)
+
+	// Sessions bind a login to its browser, kept in Postgres so the callback
+	// can reach any instance
+	sessionStorage := storage.NewPostgresStorage(ctx, q)
+
+	emailDomains, err := auth.EmailDomainsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	authService := auth.NewOAuthService(oAuthRepo, emailDomains, adminService, staffService, accessRequestService, providers...)
 
 	port := os.Getenv("PORT")
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		// Bodies are streamed so the import routes can accept uploads over
+		// the default limit, NewBodyLimit enforces the limit per route
+		StreamRequestBody: true,
+	})
 
 	// Middlewares
+	// Student roster and registration imports can be several megabytes
+	app.Use(middleware.NewBodyLimit(fiber.DefaultBodyLimit, 32*1024*1024))
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     os.Getenv("WEB_URL"),
 		AllowCredentials: true,
 	}))
+	app.Use(middleware.NewCsrf(os.Getenv("WEB_URL")))
 
 	rest.NewAdminHandler(app, adminService)
-	rest.NewEventHandler(app, adminService, eventService, staffService, participantService)
-	rest.NewAuthHandler(app, authService, tokenService)
+	rest.NewEventHandler(app, adminService, eventService, staffService, participantService, sessionService, feedService, apiKeyService, registrationService)
+	rest.NewAuthHandler(app, authService, tokenService, sessionStorage)
+	rest.NewStudentHandler(app, studentService)
+	rest.NewAuditHandler(app, auditService)
+	rest.NewAccessRequestHandler(app, accessRequestService)
+	rest.NewJwksHandler(app, signingKeyService)
 
 	log.Fatal(app.Listen(fmt.Sprintf(":%s", port)))
 }
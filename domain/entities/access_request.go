@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending  AccessRequestStatus = "pending"
+	AccessRequestApproved AccessRequestStatus = "approved"
+	AccessRequestDenied   AccessRequestStatus = "denied"
+)
+
+type AccessRequest struct {
+	Id         uuid.UUID           `json:"id"`
+	Email      string              `json:"email"`
+	FullName   string              `json:"fullName"`
+	Status     AccessRequestStatus `json:"status"`
+	ReviewedBy *string             `json:"reviewedBy"`
+	ReviewedAt *time.Time          `json:"reviewedAt"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiKey lets a kiosk scan participants into one event without signing in.
+// Only the hash of the key is stored, Prefix is kept so admins can tell keys
+// apart.
+type ApiKey struct {
+	Id         uuid.UUID  `json:"id"`
+	EventId    uuid.UUID  `json:"eventId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttendanceEvent struct {
+	Id    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Place string    `json:"place"`
+	Date  time.Time `json:"date"`
+	Host  string    `json:"host"`
+}
+
+type Attendance struct {
+	Event        AttendanceEvent `json:"event"`
+	Timestamp    time.Time       `json:"timestamp"`
+	CheckedOutAt *time.Time      `json:"checkedOutAt"`
+	DwellSeconds *int64          `json:"dwellSeconds"`
+}
+
+type AttendanceSummary struct {
+	TotalEvents       int64 `json:"totalEvents"`
+	TotalDwellSeconds int64 `json:"totalDwellSeconds"`
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor is the user a change is recorded against in the audit log.
+type Actor struct {
+	Email string
+	Role  string
+	Ip    string
+}
+
+type AuditLog struct {
+	Id         uuid.UUID       `json:"id"`
+	ActorEmail string          `json:"actorEmail"`
+	ActorRole  string          `json:"actorRole"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetId   string          `json:"targetId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	Ip         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditFilter narrows the audit log. Empty fields and nil bounds match
+// everything.
+type AuditFilter struct {
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetId   string
+	From       *time.Time
+	To         *time.Time
+}
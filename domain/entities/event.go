@@ -1,16 +1,69 @@
 package entities
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type EventStatus string
+
+const (
+	EventDraft     EventStatus = "draft"
+	EventPublished EventStatus = "published"
+	EventOpen      EventStatus = "open"
+	EventClosed    EventStatus = "closed"
+	EventArchived  EventStatus = "archived"
+)
+
+// eventTransitions lists the statuses an event can move to from each status.
+// A closed event can be reopened, an archived event is final.
+var eventTransitions = map[EventStatus][]EventStatus{
+	EventDraft:     {EventPublished, EventArchived},
+	EventPublished: {EventOpen, EventArchived},
+	EventOpen:      {EventClosed},
+	EventClosed:    {EventOpen, EventArchived},
+}
+
+func (s EventStatus) IsValid() bool {
+	switch s {
+	case EventDraft, EventPublished, EventOpen, EventClosed, EventArchived:
+		return true
+	}
+	return false
+}
+
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	return slices.Contains(eventTransitions[s], next)
+}
+
+// IsLocked reports whether the event is over and its participants should no
+// longer change.
+func (s EventStatus) IsLocked() bool {
+	return s == EventClosed || s == EventArchived
+}
+
 type Event struct {
-	Id    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Place string    `json:"place"`
-	Date  time.Time `json:"date"`
-	Host  string    `json:"host"`
-	Owner string    `json:"owner"`
+	Id                 uuid.UUID        `json:"id"`
+	Name               string           `json:"name"`
+	Place              string           `json:"place"`
+	Date               time.Time        `json:"date"`
+	Host               string           `json:"host"`
+	Owner              string           `json:"owner"`
+	StartAt            *time.Time       `json:"startAt"`
+	EndAt              *time.Time       `json:"endAt"`
+	GraceBeforeMinutes int32            `json:"graceBeforeMinutes"`
+	GraceAfterMinutes  int32            `json:"graceAfterMinutes"`
+	Status             EventStatus      `json:"status"`
+	Capacity           *int32           `json:"capacity"`
+	RegistrationMode   RegistrationMode `json:"registrationMode"`
+}
+
+// EventCapacity is the live seat count of an event. Capacity and Remaining
+// are nil when the event has no limit.
+type EventCapacity struct {
+	Capacity  *int32 `json:"capacity"`
+	Admitted  int64  `json:"admitted"`
+	Remaining *int64 `json:"remaining"`
 }
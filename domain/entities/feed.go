@@ -0,0 +1,17 @@
+package entities
+
+type FeedEventType string
+
+const (
+	FeedHeadcount          FeedEventType = "headcount"
+	FeedParticipantAdded   FeedEventType = "participant_added"
+	FeedParticipantRemoved FeedEventType = "participant_removed"
+)
+
+type FeedEvent struct {
+	Type         FeedEventType `json:"type"`
+	EventId      string        `json:"eventId"`
+	Participants []Participant `json:"participants,omitempty"`
+	Barcodes     []string      `json:"barcodes,omitempty"`
+	Headcount    int64         `json:"headcount"`
+}
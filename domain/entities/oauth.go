@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// OAuthState is a login waiting for the provider to redirect back. It is
+// bound to the browser session that started it and can only be taken once.
+type OAuthState struct {
+	State     string
+	Provider  string
+	Binding   string
+	Verifier  string
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// ExchangeCode binds a finished native login to its user and session until
+// the app redeems the code with the PKCE verifier it started with. No tokens
+// are kept, they are issued on redemption, and only the hash of the code is
+// stored.
+type ExchangeCode struct {
+	CodeHash      string
+	CodeChallenge string
+	SessionId     string
+	Email         string
+	Name          string
+	Picture       string
+	Role          string
+	ExpiresAt     time.Time
+}
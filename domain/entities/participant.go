@@ -2,9 +2,69 @@ package entities
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type StudentBarcode struct {
+	StudentId string `json:"studentId"`
+	Campus    string `json:"campus"`
+	Faculty   string `json:"faculty"`
+	EntryYear int32  `json:"entryYear"`
+}
+
 type Participant struct {
 	Barcode   string    `json:"barcode"`
 	Timestamp time.Time `json:"timestamp"`
+	StudentBarcode
+	CheckedOutAt *time.Time `json:"checkedOutAt"`
+	DwellSeconds *int64     `json:"dwellSeconds"`
+	Student      *Student   `json:"student"`
+	ScanId       *uuid.UUID `json:"scanId"`
+	ScannedBy    string     `json:"scannedBy"`
+	DeviceId     string     `json:"deviceId"`
+	ApiKeyId     *uuid.UUID `json:"apiKeyId"`
+	// Registered is only set on a new scan when the event checks its
+	// registration list.
+	Registered *bool `json:"registered,omitempty"`
+}
+
+// ScanActor is the staff member and device a scan came from. Scans made by a
+// kiosk carry the API key instead of an email.
+type ScanActor struct {
+	Actor
+	DeviceId string
+	ApiKeyId *uuid.UUID
+}
+
+type StaffScanCount struct {
+	ScannedBy   string    `json:"scannedBy"`
+	Scans       int64     `json:"scans"`
+	FirstScanAt time.Time `json:"firstScanAt"`
+	LastScanAt  time.Time `json:"lastScanAt"`
+}
+
+type StaffScanBucket struct {
+	ScannedBy string    `json:"scannedBy"`
+	Bucket    time.Time `json:"bucket"`
+	Scans     int64     `json:"scans"`
+}
+
+type ScanStatus string
+
+const (
+	ScanInserted        ScanStatus = "inserted"
+	ScanDuplicate       ScanStatus = "duplicate"
+	ScanAlreadyRecorded ScanStatus = "already_recorded"
+	ScanIdConflict      ScanStatus = "scan_id_conflict"
+	ScanRejected        ScanStatus = "rejected"
+	ScanOutOfWindow     ScanStatus = "out_of_window"
+	ScanEventFull       ScanStatus = "event_full"
+	ScanUnregistered    ScanStatus = "not_registered"
+)
+
+type ScanResult struct {
+	ScanId string     `json:"scanId"`
+	Status ScanStatus `json:"status"`
+	Reason string     `json:"reason,omitempty"`
 }
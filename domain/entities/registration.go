@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// RegistrationMode decides how scans are checked against the registration
+// list of an event.
+type RegistrationMode string
+
+const (
+	// RegistrationOpen accepts any student.
+	RegistrationOpen RegistrationMode = "open"
+	// RegistrationRequired rejects students that are not registered.
+	RegistrationRequired RegistrationMode = "registered_only"
+	// RegistrationWarn accepts unregistered students as walk-ins but flags
+	// the scan.
+	RegistrationWarn RegistrationMode = "warn_unregistered"
+)
+
+func (m RegistrationMode) IsValid() bool {
+	switch m {
+	case RegistrationOpen, RegistrationRequired, RegistrationWarn:
+		return true
+	}
+	return false
+}
+
+// Registration is a student expected at an event.
+type Registration struct {
+	StudentId string    `json:"studentId"`
+	FullName  *string   `json:"fullName"`
+	Attended  bool      `json:"attended"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RegistrationSummary compares the registration list of an event with its
+// participants. Walk-ins are participants that were not registered.
+type RegistrationSummary struct {
+	Registered int64 `json:"registered"`
+	Attended   int64 `json:"attended"`
+	NoShows    int64 `json:"noShows"`
+	WalkIns    int64 `json:"walkIns"`
+}
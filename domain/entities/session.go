@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	Id                uuid.UUID `json:"id"`
+	EventId           uuid.UUID `json:"eventId"`
+	Name              string    `json:"name"`
+	StartAt           time.Time `json:"startAt"`
+	EndAt             time.Time `json:"endAt"`
+	ParticipantsCount int64     `json:"participantsCount"`
+}
+
+type SessionAttendance struct {
+	Barcode          string `json:"barcode"`
+	SessionsAttended int64  `json:"sessionsAttended"`
+	TotalSessions    int64  `json:"totalSessions"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// SigningKey is published in the JWKS from CreatedAt and signs tokens from
+// ActiveFrom until a newer key becomes active. It stays published until
+// ExpiresAt so tokens it signed can still be verified.
+type SigningKey struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	ActiveFrom time.Time
+}
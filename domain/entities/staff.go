@@ -1,5 +1,31 @@
 package entities
 
+type StaffRole string
+
+const (
+	StaffScanner     StaffRole = "scanner"
+	StaffSupervisor  StaffRole = "supervisor"
+	StaffCoOrganiser StaffRole = "co_organiser"
+)
+
+var staffRoleRanks = map[StaffRole]int{
+	StaffScanner:     1,
+	StaffSupervisor:  2,
+	StaffCoOrganiser: 3,
+}
+
+func (r StaffRole) IsValid() bool {
+	_, ok := staffRoleRanks[r]
+	return ok
+}
+
+// Includes reports whether the role grants at least the permissions of
+// required. Each role can do everything the roles below it can.
+func (r StaffRole) Includes(required StaffRole) bool {
+	return staffRoleRanks[r] >= staffRoleRanks[required] && staffRoleRanks[r] > 0
+}
+
 type Staff struct {
-	Email string `json:"email"`
+	Email string    `json:"email"`
+	Role  StaffRole `json:"role"`
 }
@@ -0,0 +1,8 @@
+package entities
+
+type Student struct {
+	StudentId string `json:"studentId"`
+	FullName  string `json:"fullName"`
+	Faculty   string `json:"faculty"`
+	Major     string `json:"major"`
+}
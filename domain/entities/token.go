@@ -1,6 +1,22 @@
 package entities
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one signed-in device of a user. Each device keeps its own
+// refresh token so signing in elsewhere does not sign it out. Id is the token
+// family shared by every token rotated from the same sign-in, and only the
+// hash of the latest one is stored.
 type RefreshToken struct {
-	Email string `json:"email"`
-	Token string `json:"token"`
+	Id         uuid.UUID `json:"id"`
+	Email      string    `json:"email"`
+	TokenHash  string    `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	Ip         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Current    bool      `json:"current"`
 }
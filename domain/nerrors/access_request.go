@@ -0,0 +1,10 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrAccessRequestNotFound        = errors.New("access request not found")
+	ErrAccessRequestAlreadyReviewed = errors.New("access request already reviewed")
+	ErrAccessRequestPending         = errors.New("access request pending")
+	ErrEmailDomainNotAllowed        = errors.New("email domain not allowed")
+)
@@ -0,0 +1,11 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrApiKeyNotFound      = errors.New("api key not found")
+	ErrApiKeyExpired       = errors.New("api key expired")
+	ErrApiKeyRevoked       = errors.New("api key revoked")
+	ErrApiKeyNotAllowed    = errors.New("api key not allowed for this event")
+	ErrInvalidApiKeyExpiry = errors.New("api key expiry must be in the future")
+)
@@ -0,0 +1,12 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrInvalidOAuthState     = errors.New("invalid oauth state")
+	ErrInvalidOAuthNonce     = errors.New("invalid oauth nonce")
+	ErrOAuthExchange         = errors.New("cannot exchange oauth code")
+	ErrOAuthProviderNotFound = errors.New("oauth provider not found")
+	ErrInvalidExchangeCode   = errors.New("invalid exchange code")
+	ErrEmailNotVerified      = errors.New("email not verified")
+)
@@ -3,7 +3,10 @@ package nerrors
 import "errors"
 
 var (
-	ErrSomethingWentWrong = errors.New("something went wrong")
-	ErrCannotParseUUID    = errors.New("cannot parse uuid")
-	ErrUserNotFound = errors.New("user not found")
+	ErrSomethingWentWrong    = errors.New("something went wrong")
+	ErrCannotParseUUID       = errors.New("cannot parse uuid")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrUnsupportedFileFormat = errors.New("unsupported file format")
+	ErrInvalidDateRange      = errors.New("invalid date range")
+	ErrMessageTooLarge       = errors.New("message too large")
 )
@@ -5,4 +5,11 @@ import "errors"
 var (
 	ErrEventNotFound      = errors.New("event not found")
 	ErrEventAlreadyExists = errors.New("event already exists")
+	ErrInvalidScanWindow  = errors.New("scan window end is before start")
+	ErrScanOutsideWindow  = errors.New("scan is outside of event scan window")
+	ErrInvalidEventStatus = errors.New("invalid event status")
+	ErrEventTransition    = errors.New("event cannot move to this status")
+	ErrEventNotOpen       = errors.New("event is not open for scanning")
+	ErrEventLocked        = errors.New("event is closed or archived")
+	ErrEventFull          = errors.New("event is full")
 )
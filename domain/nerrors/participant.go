@@ -3,5 +3,10 @@ package nerrors
 import "errors"
 
 var (
-	ErrParticipantAlreadyExists = errors.New("participant already exists")
+	ErrParticipantAlreadyExists     = errors.New("participant already exists")
+	ErrParticipantNotFound          = errors.New("participant not found")
+	ErrParticipantAlreadyCheckedOut = errors.New("participant already checked out")
+	ErrCheckOutBeforeCheckIn        = errors.New("check out time is before check in time")
+	ErrInvalidTimelineInterval      = errors.New("invalid timeline interval")
+	ErrInvalidBarcode               = errors.New("invalid student barcode")
 )
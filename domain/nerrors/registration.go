@@ -0,0 +1,10 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrNotRegistered           = errors.New("student is not registered for this event")
+	ErrRegistrationNotFound    = errors.New("registration not found")
+	ErrInvalidRegistrationMode = errors.New("invalid registration mode")
+	ErrInvalidRegistrationList = errors.New("invalid registration list")
+)
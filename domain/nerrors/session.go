@@ -0,0 +1,8 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrInvalidSessionWindow = errors.New("session end is before start")
+)
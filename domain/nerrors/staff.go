@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrStaffAlreadyExists = errors.New("staff already exists")
 	ErrStaffNotFound      = errors.New("staff not found")
+	ErrInvalidStaffRole   = errors.New("invalid staff role")
 )
@@ -0,0 +1,8 @@
+package nerrors
+
+import "errors"
+
+var (
+	ErrStudentNotFound      = errors.New("student not found")
+	ErrInvalidStudentRoster = errors.New("invalid student roster")
+)
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/google/uuid"
+)
+
+type AccessRequestRepository interface {
+	Create(email string, fullName string) error
+	GetById(id uuid.UUID) (*entities.AccessRequest, error)
+	GetPagination(status string, search string, pageIndex int32, pageSize int32) ([]entities.AccessRequest, error)
+	Count(status string, search string) (int64, error)
+	Review(id uuid.UUID, status entities.AccessRequestStatus, reviewedBy string) error
+}
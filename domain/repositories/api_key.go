@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/google/uuid"
+)
+
+type ApiKeyRepository interface {
+	Create(key *entities.ApiKey) (*entities.ApiKey, error)
+	GetByHash(keyHash string) (*entities.ApiKey, error)
+	GetByEventId(eventId uuid.UUID) ([]entities.ApiKey, error)
+	Revoke(eventId uuid.UUID, id uuid.UUID) error
+	Touch(id uuid.UUID) error
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+
+type AuditRepository interface {
+	Create(log *entities.AuditLog) error
+	GetPagination(filter *entities.AuditFilter, pageIndex int32, pageSize int32) ([]entities.AuditLog, error)
+	Count(filter *entities.AuditFilter) (int64, error)
+}
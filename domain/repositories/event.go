@@ -6,10 +6,14 @@ import (
 )
 
 type EventRepository interface {
-	GetPagination(search string, pageIndex int32, pageSize int32) ([]*entities.Event, error)
-	GetCount(search string) (int64, error)
+	GetPagination(search string, status string, pageIndex int32, pageSize int32) ([]*entities.Event, error)
+	GetCount(search string, status string) (int64, error)
 	GetById(id uuid.UUID) (*entities.Event, error)
-	Create(e *entities.Event, adminId string) error
+	Create(e *entities.Event, adminId string) (uuid.UUID, error)
 	DeleteById(id uuid.UUID) error
 	UpdateById(id uuid.UUID, e *entities.Event) error
+	UpdateStatus(id uuid.UUID, from entities.EventStatus, to entities.EventStatus) error
+	GetCapacity(id uuid.UUID) (*entities.EventCapacity, error)
+	UpdateCapacity(id uuid.UUID, capacity *int32) error
+	UpdateRegistrationMode(id uuid.UUID, mode entities.RegistrationMode) error
 }
@@ -0,0 +1,14 @@
+package repositories
+
+import "github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+
+// OAuthRepository keeps pending logins and exchange codes where every
+// instance can see them. Take removes the row it returns, so each state and
+// code works once even when two requests race for it.
+type OAuthRepository interface {
+	CreateState(state *entities.OAuthState) error
+	TakeState(state string) (*entities.OAuthState, error)
+	CreateExchangeCode(code *entities.ExchangeCode) error
+	TakeExchangeCode(codeHash string) (*entities.ExchangeCode, error)
+	DeleteExpired() error
+}
@@ -8,8 +8,18 @@ import (
 )
 
 type ParticipantRepository interface {
-	AddParticipant(eventId uuid.UUID, barcode string, timestamp time.Time) (*entities.Participant, error)
+	AddParticipant(eventId uuid.UUID, participant *entities.Participant) (*entities.Participant, error)
+	AddSessionParticipant(eventId uuid.UUID, sessionId uuid.UUID, participant *entities.Participant) (*entities.Participant, error)
+	GetParticipant(eventId uuid.UUID, barcode string) (*entities.Participant, error)
+	GetParticipantsByBarcodes(eventId uuid.UUID, barcodes []string) ([]entities.Participant, error)
+	CheckOutParticipant(eventId uuid.UUID, barcode string, timestamp time.Time) (*entities.Participant, error)
 	GetParticipants(eventId uuid.UUID, barcode string, pageIndex int32, pageSize int32) ([]entities.Participant, error)
+	GetParticipantsAfter(eventId uuid.UUID, after *entities.Participant, limit int32) ([]entities.Participant, error)
 	CountParticipants(evenId uuid.UUID, barcode string) (*int64, error)
 	RemoveParticipants(eventId uuid.UUID, barcode []string) error
+	SyncParticipants(eventId uuid.UUID, participants []entities.Participant) ([]entities.ScanStatus, error)
+	GetAttendances(barcode string, from *time.Time, to *time.Time, pageIndex int32, pageSize int32) ([]entities.Attendance, error)
+	GetAttendanceSummary(barcode string, from *time.Time, to *time.Time) (*entities.AttendanceSummary, error)
+	GetStaffScanCounts(eventId uuid.UUID) ([]entities.StaffScanCount, error)
+	GetStaffScanTimeline(eventId uuid.UUID, interval string) ([]entities.StaffScanBucket, error)
 }
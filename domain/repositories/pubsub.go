@@ -0,0 +1,9 @@
+package repositories
+
+// PubSub delivers every message published on a topic to the subscribers of
+// that topic, which may live on other server instances. Publish returns
+// nerrors.ErrMessageTooLarge for a message the transport cannot carry.
+type PubSub interface {
+	Publish(topic string, message []byte) error
+	Subscribe(topic string) (<-chan []byte, func(), error)
+}
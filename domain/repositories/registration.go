@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/google/uuid"
+)
+
+type RegistrationRepository interface {
+	Create(eventId uuid.UUID, studentIds []string, createdBy string) (int64, error)
+	GetPagination(eventId uuid.UUID, search string, pageIndex int32, pageSize int32) ([]entities.Registration, error)
+	Count(eventId uuid.UUID, search string) (int64, error)
+	GetRegistered(eventId uuid.UUID, studentIds []string) ([]string, error)
+	Delete(eventId uuid.UUID, studentId string) error
+	GetSummary(eventId uuid.UUID) (*entities.RegistrationSummary, error)
+}
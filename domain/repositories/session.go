@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	GetAllFromEvent(eventId uuid.UUID) ([]*entities.Session, error)
+	GetById(id uuid.UUID) (*entities.Session, error)
+	CountFromEvent(eventId uuid.UUID) (int64, error)
+	Create(s *entities.Session) (*entities.Session, error)
+	UpdateById(id uuid.UUID, s *entities.Session) error
+	DeleteById(id uuid.UUID) error
+	GetAttendance(eventId uuid.UUID, barcode string, pageIndex int32, pageSize int32) ([]entities.SessionAttendance, error)
+	CountAttendance(eventId uuid.UUID, barcode string) (int64, error)
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+
+type SigningKeyRepository interface {
+	Create(key *entities.SigningKey) error
+	GetActive() ([]entities.SigningKey, error)
+	DeleteExpired() error
+}
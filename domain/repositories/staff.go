@@ -6,8 +6,8 @@ import (
 )
 
 type StaffRepository interface {
-	AddStaffs(email []string, eventId uuid.UUID) error
-	DeleteAll(eventId uuid.UUID) error
+	ReplaceAll(staffs []entities.Staff, eventId uuid.UUID) error
+	Upsert(staff *entities.Staff, eventId uuid.UUID) error
 	GetAllFromEvent(id *uuid.UUID) ([]*entities.Staff, error)
 	GetByEmail(email string) ([]entities.Staff, error)
 	GetByEmailAndEventId(email string, eventId uuid.UUID) (*entities.Staff, error)
@@ -0,0 +1,10 @@
+package repositories
+
+import "github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+
+type StudentRepository interface {
+	Upsert(students []entities.Student) error
+	GetById(studentId string) (*entities.Student, error)
+	GetPagination(search string, pageIndex int32, pageSize int32) ([]entities.Student, error)
+	Count(search string) (int64, error)
+}
@@ -1,9 +1,17 @@
 package repositories
 
-import "github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/google/uuid"
+)
 
 type TokenRepository interface {
-	GetRefreshToken(email string) (*entities.RefreshToken, error)
-	AddRefreshToken(email string, token string) error
-	RemoveRefreshToken(email string) error
+	GetRefreshToken(id uuid.UUID) (*entities.RefreshToken, error)
+	GetRefreshTokensByEmail(email string) ([]entities.RefreshToken, error)
+	AddRefreshToken(token *entities.RefreshToken) error
+	RotateRefreshToken(token *entities.RefreshToken, previousHash string) error
+	RemoveRefreshToken(id uuid.UUID, email string) error
+	RemoveRefreshTokensByEmail(email string) error
+	GetTokenVersion(email string) (int32, error)
+	BumpTokenVersion(email string) error
 }
@@ -0,0 +1,16 @@
+package requests
+
+type AccessRequestQuery struct {
+	Status    string `query:"status" validate:"omitempty,oneof=pending approved denied"`
+	Search    string `query:"search"`
+	PageIndex string `query:"pageIndex"`
+	PageSize  string `query:"pageSize"`
+}
+
+// ApproveAccessRequest grants either admin access or staff access to a single
+// event. StaffRole defaults to supervisor.
+type ApproveAccessRequest struct {
+	Role      string `json:"role" validate:"required,oneof=admin staff"`
+	EventId   string `json:"eventId" validate:"required_if=Role staff,omitempty,uuid"`
+	StaffRole string `json:"staffRole" validate:"omitempty,oneof=scanner supervisor co_organiser"`
+}
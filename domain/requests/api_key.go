@@ -0,0 +1,6 @@
+package requests
+
+type ApiKeyRequest struct {
+	Name      string `json:"name" validate:"required,min=1,max=255"`
+	ExpiresAt string `json:"expiresAt" validate:"required,timestamp"`
+}
@@ -0,0 +1,12 @@
+package requests
+
+type AuditLogQuery struct {
+	ActorEmail string `query:"actor"`
+	Action     string `query:"action"`
+	TargetType string `query:"targetType"`
+	TargetId   string `query:"targetId"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	PageIndex  string `query:"pageIndex"`
+	PageSize   string `query:"pageSize"`
+}
@@ -0,0 +1,16 @@
+package requests
+
+// TokenExchangeRequest trades the one-time code of a native login for tokens.
+type TokenExchangeRequest struct {
+	Code         string `json:"code" validate:"required"`
+	CodeVerifier string `json:"codeVerifier" validate:"required"`
+}
+
+type RefreshTokenRequest struct {
+	AccessToken  string `json:"accessToken" validate:"required"`
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
@@ -1,8 +1,20 @@
 package requests
 
+// EventRequest creates or updates an event. Capacity is only read on create,
+// afterwards it is changed with EventCapacityRequest.
 type EventRequest struct {
-	Name  string `json:"name" validate:"required,min=1"`
-	Place string `json:"place" validate:"required,min=1"`
-	Date  string `json:"date" validate:"required,date"`
-	Host  string `json:"host" validate:"required,min=1"`
+	Name               string `json:"name" validate:"required,min=1"`
+	Place              string `json:"place" validate:"required,min=1"`
+	Date               string `json:"date" validate:"required,date"`
+	Host               string `json:"host" validate:"required,min=1"`
+	StartAt            string `json:"startAt" validate:"omitempty,timestamp"`
+	EndAt              string `json:"endAt" validate:"omitempty,timestamp"`
+	GraceBeforeMinutes int32  `json:"graceBeforeMinutes" validate:"min=0"`
+	GraceAfterMinutes  int32  `json:"graceAfterMinutes" validate:"min=0"`
+	Capacity           *int32 `json:"capacity" validate:"omitempty,min=1"`
+}
+
+// EventCapacityRequest sets the event capacity, null removes the limit.
+type EventCapacityRequest struct {
+	Capacity *int32 `json:"capacity" validate:"omitempty,min=1"`
 }
@@ -1,6 +1,19 @@
 package requests
 
 type AddParticipant struct {
-	Barcode   string    `json:"barcode" validate:"required"`
+	Barcode   string `json:"barcode" validate:"required,max=14"`
 	Timestamp string `json:"timestamp" validate:"required,timestamp"`
+	SessionId string `json:"sessionId" validate:"omitempty,uuid"`
+}
+
+// SyncScan is a scan queued on a device while offline. Barcode and timestamp
+// are checked per scan so one bad entry does not reject the whole batch.
+type SyncScan struct {
+	ScanId    string `json:"scanId" validate:"required,uuid"`
+	Barcode   string `json:"barcode"`
+	Timestamp string `json:"timestamp"`
+}
+
+type SyncParticipants struct {
+	Scans []SyncScan `json:"scans" validate:"required,min=1,max=1000,dive"`
 }
@@ -0,0 +1,9 @@
+package requests
+
+type RegistrationRequest struct {
+	StudentIds []string `json:"studentIds" validate:"required,min=1,max=1000,dive,len=10,numeric"`
+}
+
+type RegistrationModeRequest struct {
+	Mode string `json:"mode" validate:"required,oneof=open registered_only warn_unregistered"`
+}
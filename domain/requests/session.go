@@ -0,0 +1,7 @@
+package requests
+
+type SessionRequest struct {
+	Name    string `json:"name" validate:"required,min=1"`
+	StartAt string `json:"startAt" validate:"required,timestamp"`
+	EndAt   string `json:"endAt" validate:"required,timestamp"`
+}
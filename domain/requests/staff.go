@@ -1,5 +1,14 @@
 package requests
 
+type StaffRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=scanner supervisor co_organiser"`
+}
+
+// SetStaffRequest replaces the staff of an event. Emails listed without a
+// role are added as supervisors, which is what every staff member could do
+// before roles existed.
 type SetStaffRequest struct {
-	Email []string `json:"emails" validate:"required"`
+	Email  []string       `json:"emails" validate:"required_without=Staffs"`
+	Staffs []StaffRequest `json:"staffs" validate:"required_without=Email,dive"`
 }
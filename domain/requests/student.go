@@ -0,0 +1,8 @@
+package requests
+
+type StudentRequest struct {
+	StudentId string `json:"studentId" validate:"required,len=10,numeric"`
+	FullName  string `json:"fullName" validate:"required,min=1"`
+	Faculty   string `json:"faculty" validate:"required,min=1"`
+	Major     string `json:"major" validate:"required,min=1"`
+}
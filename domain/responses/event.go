@@ -3,15 +3,23 @@ package responses
 import (
 	"time"
 
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
 	"github.com/google/uuid"
 )
 
 type EventResponse struct {
-	ID                uuid.UUID `json:"id"`
-	Name              string    `json:"name"`
-	Place             string    `json:"place"`
-	Date              time.Time `json:"date"`
-	Host              string    `json:"host"`
-	Owner             string    `json:"owner"`
-	ParticipantsCount int64     `json:"participants_count"`
+	ID                 uuid.UUID                 `json:"id"`
+	Name               string                    `json:"name"`
+	Place              string                    `json:"place"`
+	Date               time.Time                 `json:"date"`
+	Host               string                    `json:"host"`
+	Owner              string                    `json:"owner"`
+	StartAt            *time.Time                `json:"startAt"`
+	EndAt              *time.Time                `json:"endAt"`
+	GraceBeforeMinutes int32                     `json:"graceBeforeMinutes"`
+	GraceAfterMinutes  int32                     `json:"graceAfterMinutes"`
+	Status             entities.EventStatus      `json:"status"`
+	Capacity           *int32                    `json:"capacity"`
+	RegistrationMode   entities.RegistrationMode `json:"registrationMode"`
+	ParticipantsCount  int64                     `json:"participants_count"`
 }
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/google/uuid"
+)
+
+type AccessRequestService interface {
+	Request(email string, fullName string) error
+	GetPagination(r *requests.AccessRequestQuery) ([]entities.AccessRequest, error)
+	Count(r *requests.AccessRequestQuery) (int64, error)
+	Approve(id string, actor *entities.Actor, r *requests.ApproveAccessRequest) (*entities.AccessRequest, error)
+	Deny(id string, actor *entities.Actor) (*entities.AccessRequest, error)
+}
+
+type accessRequestService struct {
+	repo         repositories.AccessRequestRepository
+	adminService AdminService
+	staffService StaffService
+	audit        AuditService
+}
+
+func NewAccessRequestService(repo repositories.AccessRequestRepository, adminService AdminService, staffService StaffService, audit AuditService) AccessRequestService {
+	return &accessRequestService{
+		repo:         repo,
+		adminService: adminService,
+		staffService: staffService,
+		audit:        audit,
+	}
+}
+
+// Request records that email signed in without any role. Signing in again
+// while a request is pending does not open another one.
+func (s *accessRequestService) Request(email string, fullName string) error {
+	if fullName == "" {
+		fullName = email
+	}
+
+	return s.repo.Create(email, fullName)
+}
+
+func (s *accessRequestService) GetPagination(r *requests.AccessRequestQuery) ([]entities.AccessRequest, error) {
+	parsedIndex, err := strconv.ParseInt(r.PageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(r.PageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	accessRequests, err := s.repo.GetPagination(r.Status, r.Search, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if accessRequests == nil {
+		return []entities.AccessRequest{}, nil
+	}
+
+	return accessRequests, nil
+}
+
+func (s *accessRequestService) Count(r *requests.AccessRequestQuery) (int64, error) {
+	return s.repo.Count(r.Status, r.Search)
+}
+
+func (s *accessRequestService) getPending(id string) (*entities.AccessRequest, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	accessRequest, err := s.repo.GetById(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessRequest.Status != entities.AccessRequestPending {
+		return nil, nerrors.ErrAccessRequestAlreadyReviewed
+	}
+
+	return accessRequest, nil
+}
+
+func (s *accessRequestService) grant(accessRequest *entities.AccessRequest, actor *entities.Actor, r *requests.ApproveAccessRequest) error {
+	if r.Role == "admin" {
+		err := s.adminService.Create(actor, &requests.AdminRequest{
+			Email:    accessRequest.Email,
+			FullName: accessRequest.FullName,
+		})
+		if err != nil && !errors.Is(err, nerrors.ErrAdminAlreadyExists) {
+			return err
+		}
+
+		return nil
+	}
+
+	role := entities.StaffSupervisor
+	if r.StaffRole != "" {
+		role = entities.StaffRole(r.StaffRole)
+	}
+
+	return s.staffService.AddStaff(r.EventId, actor, &entities.Staff{
+		Email: accessRequest.Email,
+		Role:  role,
+	})
+}
+
+// Approve grants the requested access before closing the request, so a
+// failed grant leaves the request pending to be retried.
+func (s *accessRequestService) Approve(id string, actor *entities.Actor, r *requests.ApproveAccessRequest) (*entities.AccessRequest, error) {
+	accessRequest, err := s.getPending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.grant(accessRequest, actor, r)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewer := actor.Email
+	err = s.repo.Review(accessRequest.Id, entities.AccessRequestApproved, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.audit, actor, "access_request.approve", "access_request", id, nil, r)
+
+	accessRequest.Status = entities.AccessRequestApproved
+	accessRequest.ReviewedBy = &reviewer
+
+	return accessRequest, nil
+}
+
+func (s *accessRequestService) Deny(id string, actor *entities.Actor) (*entities.AccessRequest, error) {
+	accessRequest, err := s.getPending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewer := actor.Email
+	err = s.repo.Review(accessRequest.Id, entities.AccessRequestDenied, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.audit, actor, "access_request.deny", "access_request", id, nil, nil)
+
+	accessRequest.Status = entities.AccessRequestDenied
+	accessRequest.ReviewedBy = &reviewer
+
+	return accessRequest, nil
+}
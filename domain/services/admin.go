@@ -16,20 +16,24 @@ import (
 type AdminService interface {
 	GetById(id string) (*entities.Admin, error)
 	GetByEmail(email string) (*entities.Admin, error)
-	Create(r *requests.AdminRequest) error
-	DeleteByIds(ids []string) error
-	UpdateById(id string, value *requests.AdminRequest) error
+	Create(actor *entities.Actor, r *requests.AdminRequest) error
+	DeleteByIds(ids []string, actor *entities.Actor) error
+	UpdateById(id string, actor *entities.Actor, value *requests.AdminRequest) error
 	GetAll(search string, pageIndexStr string, pageSizeStr string) ([]responses.AllAdminResponse, error)
 	CountAll(search string) (int64, error)
 }
 
 type adminService struct {
-	repo repositories.AdminRepository
+	repo      repositories.AdminRepository
+	tokenRepo repositories.TokenRepository
+	audit     AuditService
 }
 
-func NewAdminService(repo repositories.AdminRepository) *adminService {
+func NewAdminService(repo repositories.AdminRepository, tokenRepo repositories.TokenRepository, audit AuditService) *adminService {
 	return &adminService{
-		repo: repo,
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		audit:     audit,
 	}
 }
 
@@ -47,7 +51,7 @@ func (s *adminService) GetByEmail(email string) (*entities.Admin, error) {
 	return s.repo.GetByEmail(email)
 }
 
-func (s *adminService) Create(r *requests.AdminRequest) error {
+func (s *adminService) Create(actor *entities.Actor, r *requests.AdminRequest) error {
 	record, err := s.GetByEmail(r.Email)
 	if err != nil {
 		if !errors.Is(err, nerrors.ErrAdminNotFound) {
@@ -64,10 +68,17 @@ func (s *adminService) Create(r *requests.AdminRequest) error {
 		FullName: r.FullName,
 	}
 
-	return s.repo.Create(value)
+	err = s.repo.Create(value)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "admin.create", "admin", r.Email, nil, r)
+
+	return nil
 }
 
-func (s *adminService) DeleteByIds(ids []string) error {
+func (s *adminService) DeleteByIds(ids []string, actor *entities.Actor) error {
 	parsedIds := make([]uuid.UUID, 0)
 	for _, id := range ids {
 		parsedId, err := uuid.Parse(id)
@@ -77,20 +88,39 @@ func (s *adminService) DeleteByIds(ids []string) error {
 		parsedIds = append(parsedIds, parsedId)
 	}
 
+	admins := make([]*entities.Admin, 0, len(parsedIds))
 	for _, id := range parsedIds {
-		_, err := s.GetById(id.String())
+		admin, err := s.GetById(id.String())
 		if err != nil {
 			if errors.Is(err, nerrors.ErrAdminNotFound) {
 				return nerrors.ErrAdminNotFound
 			}
 			return err
 		}
+		admins = append(admins, admin)
+	}
+
+	err := s.repo.DeleteByIds(parsedIds)
+	if err != nil {
+		return err
 	}
 
-	return s.repo.DeleteByIds(parsedIds)
+	for _, admin := range admins {
+		recordAudit(s.audit, actor, "admin.delete", "admin", admin.Id.String(), admin, nil)
+	}
+
+	// Cut off the admin rights in their access tokens right away
+	for _, admin := range admins {
+		err = s.tokenRepo.BumpTokenVersion(admin.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *adminService) UpdateById(id string, value *requests.AdminRequest) error {
+func (s *adminService) UpdateById(id string, actor *entities.Actor, value *requests.AdminRequest) error {
 	parsedId, err := uuid.Parse(id)
 	if err != nil {
 		return nerrors.ErrCannotParseUUID
@@ -107,7 +137,23 @@ func (s *adminService) UpdateById(id string, value *requests.AdminRequest) error
 		return nerrors.ErrAdminAlreadyExists
 	}
 
-	return s.repo.UpdateById(parsedId, value)
+	previous, err := s.repo.GetById(parsedId)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateById(parsedId, value)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "admin.update", "admin", id, previous, value)
+
+	if previous.Email != value.Email {
+		return s.tokenRepo.BumpTokenVersion(previous.Email)
+	}
+
+	return nil
 }
 
 func (s *adminService) GetAll(search string, pageIndexStr string, pageSizeStr string) ([]responses.AllAdminResponse, error) {
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/google/uuid"
+)
+
+type ApiKeyService interface {
+	Create(eventId string, actor *entities.Actor, r *requests.ApiKeyRequest) (*entities.ApiKey, string, error)
+	GetByEventId(eventId string) ([]entities.ApiKey, error)
+	Revoke(eventId string, id string, actor *entities.Actor) error
+	Authenticate(key string, eventId string) (*entities.ApiKey, error)
+}
+
+type apiKeyService struct {
+	repo  repositories.ApiKeyRepository
+	audit AuditService
+}
+
+func NewApiKeyService(repo repositories.ApiKeyRepository, audit AuditService) ApiKeyService {
+	return &apiKeyService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// Create issues a key for the event. The plain key is only returned here,
+// afterwards it cannot be recovered.
+func (s *apiKeyService) Create(eventId string, actor *entities.Actor, r *requests.ApiKeyRequest) (*entities.ApiKey, string, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, "", nerrors.ErrCannotParseUUID
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, r.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return nil, "", nerrors.ErrInvalidApiKeyExpiry
+	}
+
+	key, err := libs.GenerateApiKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey, err := s.repo.Create(&entities.ApiKey{
+		EventId:   parsedId,
+		Name:      r.Name,
+		Prefix:    libs.ApiKeyDisplayPrefix(key),
+		KeyHash:   libs.HashToken(key),
+		CreatedBy: actor.Email,
+		ExpiresAt: expiresAt.UTC(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	recordAudit(s.audit, actor, "api_key.create", "api_key", apiKey.Id.String(), nil, apiKey)
+
+	return apiKey, key, nil
+}
+
+func (s *apiKeyService) GetByEventId(eventId string) ([]entities.ApiKey, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	keys, err := s.repo.GetByEventId(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys == nil {
+		return []entities.ApiKey{}, nil
+	}
+
+	return keys, nil
+}
+
+func (s *apiKeyService) Revoke(eventId string, id string, actor *entities.Actor) error {
+	parsedEventId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nerrors.ErrCannotParseUUID
+	}
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nerrors.ErrCannotParseUUID
+	}
+
+	err = s.repo.Revoke(parsedEventId, parsedId)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "api_key.revoke", "api_key", id, nil, nil)
+
+	return nil
+}
+
+// Authenticate checks that key is live and was issued for eventId.
+func (s *apiKeyService) Authenticate(key string, eventId string) (*entities.ApiKey, error) {
+	apiKey, err := s.repo.GetByHash(libs.HashToken(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, nerrors.ErrApiKeyRevoked
+	}
+
+	if !apiKey.ExpiresAt.After(time.Now()) {
+		return nil, nerrors.ErrApiKeyExpired
+	}
+
+	if apiKey.EventId.String() != eventId {
+		return nil, nerrors.ErrApiKeyNotAllowed
+	}
+
+	err = s.repo.Touch(apiKey.Id)
+	if err != nil {
+		log.Println("Cannot update api key last used: ", err)
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+)
+
+type AuditService interface {
+	Record(log *entities.AuditLog, before any, after any) error
+	GetPagination(r *requests.AuditLogQuery) ([]entities.AuditLog, error)
+	Count(r *requests.AuditLogQuery) (int64, error)
+}
+
+type auditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+func toAuditPayload(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(value)
+}
+
+// recordAudit logs a change made by actor. The change has already been
+// applied, so a failure to record it is only logged.
+func recordAudit(audit AuditService, actor *entities.Actor, action string, targetType string, targetId string, before any, after any) {
+	entry := &entities.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+	}
+
+	if actor != nil {
+		entry.ActorEmail = actor.Email
+		entry.ActorRole = actor.Role
+		entry.Ip = actor.Ip
+	}
+
+	err := audit.Record(entry, before, after)
+	if err != nil {
+		log.Println("Cannot record audit log: ", err)
+	}
+}
+
+// Record stores log with before and after encoded as JSON. A nil payload is
+// stored as NULL, e.g. before for a create or after for a delete.
+func (s *auditService) Record(log *entities.AuditLog, before any, after any) error {
+	var err error
+
+	log.Before, err = toAuditPayload(before)
+	if err != nil {
+		return err
+	}
+
+	log.After, err = toAuditPayload(after)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Create(log)
+}
+
+// toAuditFilter reads from and to as YYYY-MM-DD, both inclusive.
+func toAuditFilter(r *requests.AuditLogQuery) (*entities.AuditFilter, error) {
+	from, to, err := parseDateRange(r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if to != nil {
+		nextDay := to.Add(24 * time.Hour)
+		to = &nextDay
+	}
+
+	return &entities.AuditFilter{
+		ActorEmail: r.ActorEmail,
+		Action:     r.Action,
+		TargetType: r.TargetType,
+		TargetId:   r.TargetId,
+		From:       from,
+		To:         to,
+	}, nil
+}
+
+func (s *auditService) GetPagination(r *requests.AuditLogQuery) ([]entities.AuditLog, error) {
+	filter, err := toAuditFilter(r)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedIndex, err := strconv.ParseInt(r.PageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(r.PageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.repo.GetPagination(filter, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if logs == nil {
+		return []entities.AuditLog{}, nil
+	}
+
+	return logs, nil
+}
+
+func (s *auditService) Count(r *requests.AuditLogQuery) (int64, error) {
+	filter, err := toAuditFilter(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.repo.Count(filter)
+}
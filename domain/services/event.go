@@ -14,21 +14,28 @@ import (
 )
 
 type EventService interface {
-	GetPagination(search string, pageIndex string, pageSize string) ([]*entities.Event, error)
-	GetEventsCount(search string) (int64, error)
+	GetPagination(search string, status string, pageIndex string, pageSize string) ([]*entities.Event, error)
+	GetEventsCount(search string, status string) (int64, error)
 	GetById(id string) (*entities.Event, error)
-	Create(e *requests.EventRequest, adminId string) error
-	DeleteById(id string) error
-	UpdateById(id string, r *requests.EventRequest) error
+	Create(e *requests.EventRequest, adminId string, actor *entities.Actor) error
+	DeleteById(id string, actor *entities.Actor) error
+	UpdateById(id string, actor *entities.Actor, r *requests.EventRequest) error
+	UpdateStatus(id string, actor *entities.Actor, status entities.EventStatus) (*entities.Event, error)
+	CheckParticipantChanges(id string, override bool) error
+	GetCapacity(id string) (*entities.EventCapacity, error)
+	SetCapacity(id string, actor *entities.Actor, r *requests.EventCapacityRequest) (*entities.EventCapacity, error)
+	SetRegistrationMode(id string, actor *entities.Actor, mode entities.RegistrationMode) error
 }
 
 type eventService struct {
-	repo repositories.EventRepository
+	repo  repositories.EventRepository
+	audit AuditService
 }
 
-func NewEventService(repo repositories.EventRepository) EventService {
+func NewEventService(repo repositories.EventRepository, audit AuditService) EventService {
 	return &eventService{
-		repo: repo,
+		repo:  repo,
+		audit: audit,
 	}
 }
 
@@ -40,26 +47,44 @@ func parseRequestToEntity(r *requests.EventRequest) (*entities.Event, error) {
 	}
 
 	event := &entities.Event{
-		Name:  r.Name,
-		Place: r.Place,
-		Date:  date,
-		Host:  r.Host,
+		Name:               r.Name,
+		Place:              r.Place,
+		Date:               date,
+		Host:               r.Host,
+		GraceBeforeMinutes: r.GraceBeforeMinutes,
+		GraceAfterMinutes:  r.GraceAfterMinutes,
+		Capacity:           r.Capacity,
 	}
 
-	return event, nil
-}
+	if r.StartAt != "" {
+		startAt, err := time.Parse(time.RFC3339, r.StartAt)
+		if err != nil {
+			return nil, err
+		}
+		startAt = startAt.UTC()
+		event.StartAt = &startAt
+	}
 
-func (s *eventService) isEventExist(id *uuid.UUID) error {
-	_, err := s.repo.GetById(*id)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nerrors.ErrEventNotFound
+	if r.EndAt != "" {
+		endAt, err := time.Parse(time.RFC3339, r.EndAt)
+		if err != nil {
+			return nil, err
 		}
+		endAt = endAt.UTC()
+		event.EndAt = &endAt
 	}
-	return nil
+
+	if event.StartAt != nil && event.EndAt != nil && event.EndAt.Before(*event.StartAt) {
+		return nil, nerrors.ErrInvalidScanWindow
+	}
+
+	return event, nil
 }
 
-func (s *eventService) GetPagination(search string, pageIndex string, pageSize string) ([]*entities.Event, error) {
+func (s *eventService) GetPagination(search string, status string, pageIndex string, pageSize string) ([]*entities.Event, error) {
+	if status != "" && !entities.EventStatus(status).IsValid() {
+		return nil, nerrors.ErrInvalidEventStatus
+	}
 
 	parsedIndex, err := strconv.ParseInt(pageIndex, 10, 32)
 	if err != nil {
@@ -71,11 +96,11 @@ func (s *eventService) GetPagination(search string, pageIndex string, pageSize s
 		return nil, err
 	}
 
-	return s.repo.GetPagination(search, int32(parsedIndex), int32(parsedSize))
+	return s.repo.GetPagination(search, status, int32(parsedIndex), int32(parsedSize))
 }
 
-func (s *eventService) GetEventsCount(search string) (int64, error) {
-	return s.repo.GetCount(search)
+func (s *eventService) GetEventsCount(search string, status string) (int64, error) {
+	return s.repo.GetCount(search, status)
 }
 
 func (s *eventService) GetById(id string) (*entities.Event, error) {
@@ -87,44 +112,186 @@ func (s *eventService) GetById(id string) (*entities.Event, error) {
 	return s.repo.GetById(parsedId)
 }
 
-func (s *eventService) Create(r *requests.EventRequest, adminId string) error {
+func (s *eventService) Create(r *requests.EventRequest, adminId string, actor *entities.Actor) error {
 	event, err := parseRequestToEntity(r)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.Create(event, adminId)
+	id, err := s.repo.Create(event, adminId)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "event.create", "event", id.String(), nil, r)
+
+	return nil
 }
 
-func (s *eventService) DeleteById(id string) error {
+func (s *eventService) DeleteById(id string, actor *entities.Actor) error {
 	parsedId, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	err = s.isEventExist(&parsedId)
+	event, err := s.repo.GetById(parsedId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nerrors.ErrEventNotFound
+		}
+		return err
+	}
+
+	err = s.repo.DeleteById(parsedId)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.DeleteById(parsedId)
+	recordAudit(s.audit, actor, "event.delete", "event", id, event, nil)
+
+	return nil
 }
 
-func (s *eventService) UpdateById(id string, r *requests.EventRequest) error {
+func (s *eventService) UpdateById(id string, actor *entities.Actor, r *requests.EventRequest) error {
 	parsedId, err := uuid.Parse(id)
 	if err != nil {
 		return err
 	}
 
-	err = s.isEventExist(&parsedId)
+	current, err := s.repo.GetById(parsedId)
 	if err != nil {
 		return err
 	}
 
+	if current.Status == entities.EventArchived {
+		return nerrors.ErrEventLocked
+	}
+
 	event, err := parseRequestToEntity(r)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.UpdateById(parsedId, event)
+	err = s.repo.UpdateById(parsedId, event)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "event.update", "event", id, current, r)
+
+	return nil
+}
+
+// UpdateStatus moves the event to status when its current status allows it.
+func (s *eventService) UpdateStatus(id string, actor *entities.Actor, status entities.EventStatus) (*entities.Event, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	event, err := s.repo.GetById(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.Status.CanTransitionTo(status) {
+		return nil, nerrors.ErrEventTransition
+	}
+
+	err = s.repo.UpdateStatus(parsedId, event.Status, status)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.audit, actor, "event.status", "event", id, map[string]any{
+		"status": event.Status,
+	}, map[string]any{
+		"status": status,
+	})
+
+	event.Status = status
+
+	return event, nil
+}
+
+// CheckParticipantChanges returns an error unless participants of the event
+// can be scanned in, checked out or removed. Only open events accept changes,
+// override lets an admin fix up an event that is not open.
+func (s *eventService) CheckParticipantChanges(id string, override bool) error {
+	event, err := s.GetById(id)
+	if err != nil {
+		return err
+	}
+
+	if override || event.Status == entities.EventOpen {
+		return nil
+	}
+
+	if event.Status.IsLocked() {
+		return nerrors.ErrEventLocked
+	}
+
+	return nerrors.ErrEventNotOpen
+}
+
+func (s *eventService) GetCapacity(id string) (*entities.EventCapacity, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	return s.repo.GetCapacity(parsedId)
+}
+
+// SetCapacity changes the event capacity, it can be raised or removed while
+// the event is open. Lowering it below the admitted count only stops new
+// admissions.
+func (s *eventService) SetCapacity(id string, actor *entities.Actor, r *requests.EventCapacityRequest) (*entities.EventCapacity, error) {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	before, err := s.repo.GetCapacity(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.UpdateCapacity(parsedId, r.Capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.audit, actor, "event.capacity", "event", id, before, r)
+
+	return s.repo.GetCapacity(parsedId)
+}
+
+func (s *eventService) SetRegistrationMode(id string, actor *entities.Actor, mode entities.RegistrationMode) error {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nerrors.ErrCannotParseUUID
+	}
+
+	if !mode.IsValid() {
+		return nerrors.ErrInvalidRegistrationMode
+	}
+
+	event, err := s.repo.GetById(parsedId)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateRegistrationMode(parsedId, mode)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "event.registration_mode", "event", id, map[string]any{
+		"mode": event.RegistrationMode,
+	}, map[string]any{
+		"mode": mode,
+	})
+
+	return nil
 }
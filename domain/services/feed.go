@@ -0,0 +1,120 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/google/uuid"
+)
+
+type FeedService interface {
+	Publish(event *entities.FeedEvent) error
+	Subscribe(eventId string) (<-chan entities.FeedEvent, func(), error)
+}
+
+type feedService struct {
+	pubsub repositories.PubSub
+}
+
+func NewFeedService(pubsub repositories.PubSub) FeedService {
+	return &feedService{
+		pubsub: pubsub,
+	}
+}
+
+func feedTopic(eventId string) string {
+	return "events:" + eventId
+}
+
+// Publish sends event to the subscribers of its event. An event too large
+// for the pub/sub is split in halves, each carrying the same headcount.
+func (f *feedService) Publish(event *entities.FeedEvent) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = f.pubsub.Publish(feedTopic(event.EventId), message)
+	if !errors.Is(err, nerrors.ErrMessageTooLarge) {
+		return err
+	}
+
+	first, second, ok := splitFeedEvent(event)
+	if !ok {
+		return err
+	}
+
+	err = f.Publish(first)
+	if err != nil {
+		return err
+	}
+
+	return f.Publish(second)
+}
+
+func splitFeedEvent(event *entities.FeedEvent) (*entities.FeedEvent, *entities.FeedEvent, bool) {
+	first, second := *event, *event
+
+	switch {
+	case len(event.Participants) > 1:
+		half := len(event.Participants) / 2
+		first.Participants = event.Participants[:half]
+		second.Participants = event.Participants[half:]
+	case len(event.Barcodes) > 1:
+		half := len(event.Barcodes) / 2
+		first.Barcodes = event.Barcodes[:half]
+		second.Barcodes = event.Barcodes[half:]
+	default:
+		return nil, nil, false
+	}
+
+	return &first, &second, true
+}
+
+// Subscribe streams the feed of an event until the returned unsubscribe
+// function is called, which also closes the channel.
+func (f *feedService) Subscribe(eventId string) (<-chan entities.FeedEvent, func(), error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nil, nerrors.ErrCannotParseUUID
+	}
+
+	messages, unsubscribe, err := f.pubsub.Subscribe(feedTopic(parsedId.String()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan entities.FeedEvent)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+
+		for message := range messages {
+			var event entities.FeedEvent
+			if json.Unmarshal(message, &event) != nil {
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			unsubscribe()
+		})
+	}
+
+	return events, stop, nil
+}
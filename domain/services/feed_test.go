@@ -0,0 +1,91 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+)
+
+// limitedPubSub rejects messages over limit bytes like the Postgres NOTIFY
+// payload limit, and keeps the ones it accepts.
+type limitedPubSub struct {
+	limit    int
+	messages [][]byte
+}
+
+func (p *limitedPubSub) Publish(topic string, message []byte) error {
+	if len(message) > p.limit {
+		return nerrors.ErrMessageTooLarge
+	}
+
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func (p *limitedPubSub) Subscribe(topic string) (<-chan []byte, func(), error) {
+	return nil, func() {}, nil
+}
+
+func TestPublishSplitsLargeFeedEvents(t *testing.T) {
+	pubsub := &limitedPubSub{limit: 1000}
+	s := NewFeedService(pubsub)
+
+	participants := make([]entities.Participant, 100)
+	for i := range participants {
+		participants[i].Barcode = fmt.Sprintf("%013d", i)
+	}
+
+	err := s.Publish(&entities.FeedEvent{
+		Type:         entities.FeedParticipantAdded,
+		EventId:      "event",
+		Participants: participants,
+		Headcount:    100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pubsub.messages) < 2 {
+		t.Fatalf("published %d messages, want the event split", len(pubsub.messages))
+	}
+
+	seen := 0
+	for _, message := range pubsub.messages {
+		var event entities.FeedEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Headcount != 100 {
+			t.Errorf("headcount = %d, want 100", event.Headcount)
+		}
+
+		for _, participant := range event.Participants {
+			if participant.Barcode != participants[seen].Barcode {
+				t.Fatalf("participant %d = %s, want %s", seen, participant.Barcode, participants[seen].Barcode)
+			}
+			seen++
+		}
+	}
+
+	if seen != len(participants) {
+		t.Errorf("delivered %d participants, want %d", seen, len(participants))
+	}
+}
+
+func TestPublishRejectsUnsplittableFeedEvent(t *testing.T) {
+	s := NewFeedService(&limitedPubSub{limit: 10})
+
+	err := s.Publish(&entities.FeedEvent{
+		Type:      entities.FeedHeadcount,
+		EventId:   "event",
+		Headcount: 1,
+	})
+	if !errors.Is(err, nerrors.ErrMessageTooLarge) {
+		t.Errorf("err = %v, want ErrMessageTooLarge", err)
+	}
+}
@@ -3,13 +3,35 @@ package services
 import "time"
 
 type AuthToken struct {
+	SessionId           string
 	AccessToken         string
 	AccessTokenExpired  time.Time
 	RefreshToken        string
 	RefreshTokenExpired time.Time
 }
 
+// OAuthLogin is a user who finished signing in with a provider, tokens are
+// issued for it on a new session.
+type OAuthLogin struct {
+	SessionId string
+	Email     string
+	Name      string
+	Picture   string
+	Role      string
+}
+
+// OAuthService drives the authorization code flow for every registered
+// identity provider. binding identifies the browser session that started the
+// login, the callback must come from the same session.
+//
+// Native apps cannot read the cookies set by the callback, so their login
+// ends with a one-time code instead. The app redeems it with Exchange,
+// proving it started the login with the PKCE verifier, and only then are its
+// tokens issued.
 type OAuthService interface {
-	Auth() (*string, error)
-	Callback(code string, state string) (*string, *AuthToken, error)
+	Providers() []string
+	Auth(provider string, binding string) (*string, error)
+	Callback(provider string, code string, state string, binding string) (*OAuthLogin, error)
+	CreateExchangeCode(login *OAuthLogin, codeChallenge string) (string, error)
+	Exchange(code string, codeVerifier string) (*OAuthLogin, error)
 }
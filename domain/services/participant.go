@@ -1,6 +1,7 @@
 package services
 
 import (
+	"log"
 	"strconv"
 	"time"
 
@@ -8,27 +9,164 @@ import (
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
 	"github.com/google/uuid"
 )
 
 type ParticipantService interface {
-	AddParticipant(eventId string, r *requests.AddParticipant) (*entities.Participant, error)
+	AddParticipant(eventId string, actor *entities.ScanActor, r *requests.AddParticipant) (*entities.Participant, error)
+	CheckOutParticipant(eventId string, actor *entities.Actor, r *requests.AddParticipant) (*entities.Participant, error)
 	GetParticipants(eventId string, search string, pageIndex string, pageSize string) ([]entities.Participant, error)
-	RemoveParticipants(eventId string, barcode []string) error
+	ExportParticipants(eventId string, write func(participants []entities.Participant) error) error
+	RemoveParticipants(eventId string, actor *entities.Actor, barcode []string) error
 	GetCountParticipants(eventId string, search string) (*int64, error)
+	SyncParticipants(eventId string, actor *entities.ScanActor, r *requests.SyncParticipants) ([]entities.ScanResult, error)
+	GetStaffScanCounts(eventId string) ([]entities.StaffScanCount, error)
+	GetStaffScanTimeline(eventId string, interval string) ([]entities.StaffScanBucket, error)
 }
 
 type participantService struct {
-	repo repositories.ParticipantRepository
+	repo             repositories.ParticipantRepository
+	eventRepo        repositories.EventRepository
+	sessionRepo      repositories.SessionRepository
+	registrationRepo repositories.RegistrationRepository
+	feedService      FeedService
+	audit            AuditService
 }
 
-func NewParticipantService(repo repositories.ParticipantRepository) *participantService {
+func NewParticipantService(repo repositories.ParticipantRepository, eventRepo repositories.EventRepository, sessionRepo repositories.SessionRepository, registrationRepo repositories.RegistrationRepository, feedService FeedService, audit AuditService) *participantService {
 	return &participantService{
-		repo: repo,
+		repo:             repo,
+		eventRepo:        eventRepo,
+		sessionRepo:      sessionRepo,
+		registrationRepo: registrationRepo,
+		feedService:      feedService,
+		audit:            audit,
 	}
 }
 
-func (p *participantService) AddParticipant(eventId string, r *requests.AddParticipant) (*entities.Participant, error) {
+// scanAuditActor is who a scan is recorded against in the audit log. Kiosk
+// scans carry no email, so the API key stands in for it.
+func scanAuditActor(actor *entities.ScanActor) *entities.Actor {
+	if actor.ApiKeyId == nil {
+		return &actor.Actor
+	}
+
+	return &entities.Actor{
+		Email: "api_key:" + actor.ApiKeyId.String(),
+		Role:  "api_key",
+		Ip:    actor.Ip,
+	}
+}
+
+// publish sends a change to the live feed of the event together with the new
+// headcount. The change is already stored, so a feed failure is not reported
+// back to the scanner.
+func (p *participantService) publish(eventId uuid.UUID, event entities.FeedEvent) {
+	count, err := p.repo.CountParticipants(eventId, "")
+	if err != nil {
+		log.Println("Cannot count participants for the feed: ", err)
+		return
+	}
+
+	event.EventId = eventId.String()
+	event.Headcount = *count
+
+	err = p.feedService.Publish(&event)
+	if err != nil {
+		log.Println("Cannot publish feed event: ", err)
+	}
+}
+
+// isInScanWindow reports whether timestamp falls between startAt and endAt
+// widened by the event grace periods. A nil bound leaves that side open.
+func isInScanWindow(event *entities.Event, startAt *time.Time, endAt *time.Time, timestamp time.Time) bool {
+	if startAt != nil {
+		opensAt := startAt.Add(-time.Duration(event.GraceBeforeMinutes) * time.Minute)
+		if timestamp.Before(opensAt) {
+			return false
+		}
+	}
+
+	if endAt != nil {
+		closesAt := endAt.Add(time.Duration(event.GraceAfterMinutes) * time.Minute)
+		if timestamp.After(closesAt) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *participantService) checkScanWindow(eventId uuid.UUID, timestamp time.Time) error {
+	event, err := p.eventRepo.GetById(eventId)
+	if err != nil {
+		return err
+	}
+
+	if !isInScanWindow(event, event.StartAt, event.EndAt, timestamp) {
+		return nerrors.ErrScanOutsideWindow
+	}
+
+	return nil
+}
+
+// checkRegistration looks the participant up on the registration list of the
+// event and marks whether it is registered. Unregistered students are
+// rejected when the event is registered only.
+func (p *participantService) checkRegistration(eventId uuid.UUID, participant *entities.Participant) error {
+	event, err := p.eventRepo.GetById(eventId)
+	if err != nil {
+		return err
+	}
+
+	if event.RegistrationMode == entities.RegistrationOpen {
+		return nil
+	}
+
+	registered, err := p.registrationRepo.GetRegistered(eventId, []string{participant.StudentId})
+	if err != nil {
+		return err
+	}
+
+	isRegistered := len(registered) > 0
+	if !isRegistered && event.RegistrationMode == entities.RegistrationRequired {
+		return nerrors.ErrNotRegistered
+	}
+
+	participant.Registered = &isRegistered
+
+	return nil
+}
+
+func (p *participantService) addSessionParticipant(eventId uuid.UUID, sessionId string, participant *entities.Participant) (*entities.Participant, error) {
+	parsedSessionId, err := uuid.Parse(sessionId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	session, err := p.sessionRepo.GetById(parsedSessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.EventId != eventId {
+		return nil, nerrors.ErrSessionNotFound
+	}
+
+	event, err := p.eventRepo.GetById(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isInScanWindow(event, &session.StartAt, &session.EndAt, participant.Timestamp) {
+		return nil, nerrors.ErrScanOutsideWindow
+	}
+
+	return p.repo.AddSessionParticipant(eventId, session.Id, participant)
+}
+
+func (p *participantService) AddParticipant(eventId string, actor *entities.ScanActor, r *requests.AddParticipant) (*entities.Participant, error) {
 	parsedId, err := uuid.Parse(eventId)
 	if err != nil {
 		return nil, err
@@ -39,7 +177,98 @@ func (p *participantService) AddParticipant(eventId string, r *requests.AddParti
 		return nil, err
 	}
 
-	return p.repo.AddParticipant(parsedId, r.Barcode, parsedTimestamp)
+	student, err := libs.ReadStudentBarcode(r.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	participant := &entities.Participant{
+		Barcode:        r.Barcode,
+		Timestamp:      parsedTimestamp,
+		StudentBarcode: student,
+		ScannedBy:      actor.Email,
+		DeviceId:       actor.DeviceId,
+		ApiKeyId:       actor.ApiKeyId,
+	}
+
+	err = p.checkRegistration(parsedId, participant)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *entities.Participant
+	if r.SessionId != "" {
+		record, err = p.addSessionParticipant(parsedId, r.SessionId, participant)
+	} else {
+		err = p.checkScanWindow(parsedId, parsedTimestamp)
+		if err == nil {
+			record, err = p.repo.AddParticipant(parsedId, participant)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.Registered = participant.Registered
+
+	recordAudit(p.audit, scanAuditActor(actor), "participant.add", "event", eventId, nil, record)
+
+	p.publish(parsedId, entities.FeedEvent{
+		Type:         entities.FeedParticipantAdded,
+		Participants: []entities.Participant{*record},
+	})
+
+	return record, nil
+}
+
+func setDwellTime(participant *entities.Participant) {
+	if participant.CheckedOutAt == nil {
+		return
+	}
+
+	dwell := int64(participant.CheckedOutAt.Sub(participant.Timestamp).Seconds())
+	participant.DwellSeconds = &dwell
+}
+
+func (p *participantService) CheckOutParticipant(eventId string, actor *entities.Actor, r *requests.AddParticipant) (*entities.Participant, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTimestamp, err := time.Parse(time.RFC3339, r.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.checkScanWindow(parsedId, parsedTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := p.repo.GetParticipant(parsedId, r.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.CheckedOutAt != nil {
+		return nil, nerrors.ErrParticipantAlreadyCheckedOut
+	}
+
+	if parsedTimestamp.Before(record.Timestamp) {
+		return nil, nerrors.ErrCheckOutBeforeCheckIn
+	}
+
+	participant, err := p.repo.CheckOutParticipant(parsedId, r.Barcode, parsedTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	setDwellTime(participant)
+
+	recordAudit(p.audit, actor, "participant.checkout", "event", eventId, nil, participant)
+
+	return participant, nil
 }
 
 func (p *participantService) GetParticipants(eventId string, search string, pageIndex string, pageSize string) ([]entities.Participant, error) {
@@ -68,16 +297,75 @@ func (p *participantService) GetParticipants(eventId string, search string, page
 		return []entities.Participant{}, nil
 	}
 
+	for i := range participants {
+		setDwellTime(&participants[i])
+	}
+
 	return participants, nil
 }
 
-func (p *participantService) RemoveParticipants(eventId string, barcodes []string) error {
+const exportBatchSize = 1000
+
+// ExportParticipants walks every participant of the event in batches so
+// large events can be streamed without loading them all at once.
+func (p *participantService) ExportParticipants(eventId string, write func(participants []entities.Participant) error) error {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nerrors.ErrCannotParseUUID
+	}
+
+	var after *entities.Participant
+	for {
+		participants, err := p.repo.GetParticipantsAfter(parsedId, after, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(participants) == 0 {
+			return nil
+		}
+
+		for i := range participants {
+			setDwellTime(&participants[i])
+		}
+
+		err = write(participants)
+		if err != nil {
+			return err
+		}
+
+		if len(participants) < exportBatchSize {
+			return nil
+		}
+
+		after = &participants[len(participants)-1]
+	}
+}
+
+func (p *participantService) RemoveParticipants(eventId string, actor *entities.Actor, barcodes []string) error {
 	parsedEventId, err := uuid.Parse(eventId)
 	if err != nil {
 		return nerrors.ErrCannotParseUUID
 	}
 
-	return p.repo.RemoveParticipants(parsedEventId, barcodes)
+	removed, err := p.repo.GetParticipantsByBarcodes(parsedEventId, barcodes)
+	if err != nil {
+		return err
+	}
+
+	err = p.repo.RemoveParticipants(parsedEventId, barcodes)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(p.audit, actor, "participant.remove", "event", eventId, removed, nil)
+
+	p.publish(parsedEventId, entities.FeedEvent{
+		Type:     entities.FeedParticipantRemoved,
+		Barcodes: barcodes,
+	})
+
+	return nil
 }
 
 func (p *participantService) GetCountParticipants(eventId string, search string) (*int64, error) {
@@ -94,3 +382,184 @@ func (p *participantService) GetCountParticipants(eventId string, search string)
 
 	return count, nil
 }
+
+// SyncParticipants records scans queued by a device while it was offline.
+// Scans are checked against the event window using their device timestamp
+// and the valid ones are inserted together, so replaying a batch only
+// reports the already recorded scans as duplicates.
+func (p *participantService) SyncParticipants(eventId string, actor *entities.ScanActor, r *requests.SyncParticipants) ([]entities.ScanResult, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	event, err := p.eventRepo.GetById(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]entities.ScanResult, len(r.Scans))
+	participants := make([]entities.Participant, 0, len(r.Scans))
+	pending := make([]int, 0, len(r.Scans))
+
+	for i, scan := range r.Scans {
+		results[i].ScanId = scan.ScanId
+
+		scanId := uuid.MustParse(scan.ScanId)
+
+		timestamp, err := time.Parse(time.RFC3339, scan.Timestamp)
+		if err != nil {
+			results[i].Status = entities.ScanRejected
+			results[i].Reason = "Invalid timestamp"
+			continue
+		}
+
+		student, err := libs.ReadStudentBarcode(scan.Barcode)
+		if err != nil {
+			results[i].Status = entities.ScanRejected
+			results[i].Reason = "Invalid barcode"
+			continue
+		}
+
+		if !isInScanWindow(event, event.StartAt, event.EndAt, timestamp) {
+			results[i].Status = entities.ScanOutOfWindow
+			continue
+		}
+
+		participants = append(participants, entities.Participant{
+			Barcode:        scan.Barcode,
+			Timestamp:      timestamp,
+			StudentBarcode: student,
+			ScanId:         &scanId,
+			ScannedBy:      actor.Email,
+			DeviceId:       actor.DeviceId,
+			ApiKeyId:       actor.ApiKeyId,
+		})
+		pending = append(pending, i)
+	}
+
+	participants, pending, err = p.filterUnregistered(event, participants, pending, results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(participants) == 0 {
+		recordAudit(p.audit, scanAuditActor(actor), "participant.sync", "event", eventId, nil, results)
+		return results, nil
+	}
+
+	statuses, err := p.repo.SyncParticipants(parsedId, participants)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make([]entities.Participant, 0, len(participants))
+	for j, i := range pending {
+		results[i].Status = statuses[j]
+		if statuses[j] == entities.ScanInserted {
+			added = append(added, participants[j])
+		}
+	}
+
+	recordAudit(p.audit, scanAuditActor(actor), "participant.sync", "event", eventId, nil, results)
+
+	if len(added) > 0 {
+		p.publish(parsedId, entities.FeedEvent{
+			Type:         entities.FeedParticipantAdded,
+			Participants: added,
+		})
+	}
+
+	return results, nil
+}
+
+// filterUnregistered checks queued scans against the registration list of
+// the event. On a registered only event the unregistered scans are reported
+// as not_registered and dropped, on a warn event they are kept and flagged.
+func (p *participantService) filterUnregistered(event *entities.Event, participants []entities.Participant, pending []int, results []entities.ScanResult) ([]entities.Participant, []int, error) {
+	if event.RegistrationMode == entities.RegistrationOpen || len(participants) == 0 {
+		return participants, pending, nil
+	}
+
+	studentIds := make([]string, 0, len(participants))
+	for _, participant := range participants {
+		studentIds = append(studentIds, participant.StudentId)
+	}
+
+	registered, err := p.registrationRepo.GetRegistered(event.Id, studentIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isRegistered := make(map[string]bool, len(registered))
+	for _, studentId := range registered {
+		isRegistered[studentId] = true
+	}
+
+	kept := participants[:0]
+	keptPending := pending[:0]
+	for j, participant := range participants {
+		i := pending[j]
+
+		if !isRegistered[participant.StudentId] {
+			if event.RegistrationMode == entities.RegistrationRequired {
+				results[i].Status = entities.ScanUnregistered
+				continue
+			}
+			results[i].Reason = "Not registered"
+		}
+
+		kept = append(kept, participant)
+		keptPending = append(keptPending, i)
+	}
+
+	return kept, keptPending, nil
+}
+
+func (p *participantService) GetStaffScanCounts(eventId string) ([]entities.StaffScanCount, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	counts, err := p.repo.GetStaffScanCounts(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if counts == nil {
+		return []entities.StaffScanCount{}, nil
+	}
+
+	return counts, nil
+}
+
+var timelineIntervals = map[string]bool{
+	"minute": true,
+	"hour":   true,
+	"day":    true,
+}
+
+// GetStaffScanTimeline counts scans per staff member in minute, hour or day
+// buckets.
+func (p *participantService) GetStaffScanTimeline(eventId string, interval string) ([]entities.StaffScanBucket, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	if !timelineIntervals[interval] {
+		return nil, nerrors.ErrInvalidTimelineInterval
+	}
+
+	buckets, err := p.repo.GetStaffScanTimeline(parsedId, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if buckets == nil {
+		return []entities.StaffScanBucket{}, nil
+	}
+
+	return buckets, nil
+}
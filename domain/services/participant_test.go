@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/google/uuid"
+)
+
+// fakeParticipantRepo keeps synced and added participants. Methods the tests
+// do not reach are left to the embedded interface.
+type fakeParticipantRepo struct {
+	repositories.ParticipantRepository
+	added  []entities.Participant
+	synced []entities.Participant
+}
+
+func (r *fakeParticipantRepo) AddParticipant(eventId uuid.UUID, participant *entities.Participant) (*entities.Participant, error) {
+	r.added = append(r.added, *participant)
+	return participant, nil
+}
+
+func (r *fakeParticipantRepo) SyncParticipants(eventId uuid.UUID, participants []entities.Participant) ([]entities.ScanStatus, error) {
+	r.synced = append(r.synced, participants...)
+
+	statuses := make([]entities.ScanStatus, len(participants))
+	for i := range statuses {
+		statuses[i] = entities.ScanInserted
+	}
+	return statuses, nil
+}
+
+func (r *fakeParticipantRepo) CountParticipants(eventId uuid.UUID, barcode string) (*int64, error) {
+	count := int64(len(r.added) + len(r.synced))
+	return &count, nil
+}
+
+type fakeEventRepo struct {
+	repositories.EventRepository
+	event *entities.Event
+}
+
+func (r *fakeEventRepo) GetById(id uuid.UUID) (*entities.Event, error) {
+	return r.event, nil
+}
+
+type fakeAuditRepo struct {
+	repositories.AuditRepository
+	logs []entities.AuditLog
+}
+
+func (r *fakeAuditRepo) Create(log *entities.AuditLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func newTestParticipantService() (*participantService, *fakeParticipantRepo) {
+	repo := &fakeParticipantRepo{}
+	eventRepo := &fakeEventRepo{
+		event: &entities.Event{
+			Id:               uuid.New(),
+			Status:           entities.EventOpen,
+			RegistrationMode: entities.RegistrationOpen,
+		},
+	}
+
+	s := NewParticipantService(repo, eventRepo, nil, nil, NewFeedService(&limitedPubSub{limit: 1 << 20}), NewAuditService(&fakeAuditRepo{}))
+
+	return s, repo
+}
+
+func TestAddParticipantRejectsInvalidBarcode(t *testing.T) {
+	s, repo := newTestParticipantService()
+
+	_, err := s.AddParticipant(uuid.NewString(), &entities.ScanActor{}, &requests.AddParticipant{
+		Barcode:   "64105000019",
+		Timestamp: "2026-10-18T10:00:00Z",
+	})
+	if !errors.Is(err, nerrors.ErrInvalidBarcode) {
+		t.Errorf("err = %v, want ErrInvalidBarcode", err)
+	}
+
+	if len(repo.added) != 0 {
+		t.Errorf("recorded %d participants, want none", len(repo.added))
+	}
+}
+
+func TestSyncParticipantsRejectsInvalidBarcodes(t *testing.T) {
+	s, repo := newTestParticipantService()
+
+	results, err := s.SyncParticipants(uuid.NewString(), &entities.ScanActor{}, &requests.SyncParticipants{
+		Scans: []requests.SyncScan{
+			{ScanId: uuid.NewString(), Barcode: "64105000018", Timestamp: "2026-10-18T10:00:00Z"},
+			{ScanId: uuid.NewString(), Barcode: "12345", Timestamp: "2026-10-18T10:00:00Z"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].Status != entities.ScanInserted {
+		t.Errorf("valid scan status = %s, want inserted", results[0].Status)
+	}
+
+	if results[1].Status != entities.ScanRejected {
+		t.Errorf("invalid scan status = %s, want rejected", results[1].Status)
+	}
+
+	if len(repo.synced) != 1 || repo.synced[0].StudentId != "6410500001" {
+		t.Errorf("synced = %+v, want only the valid scan", repo.synced)
+	}
+}
+
+func TestSyncParticipantsAllowUnparsed(t *testing.T) {
+	format := libs.DefaultBarcodeFormat
+	format.AllowUnparsed = true
+	libs.UseBarcodeFormat(format)
+	t.Cleanup(func() { libs.UseBarcodeFormat(libs.DefaultBarcodeFormat) })
+
+	s, repo := newTestParticipantService()
+
+	results, err := s.SyncParticipants(uuid.NewString(), &entities.ScanActor{}, &requests.SyncParticipants{
+		Scans: []requests.SyncScan{
+			{ScanId: uuid.NewString(), Barcode: "12345", Timestamp: "2026-10-18T10:00:00Z"},
+			{ScanId: uuid.NewString(), Barcode: "123456789012345", Timestamp: "2026-10-18T10:00:00Z"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results[0].Status != entities.ScanInserted {
+		t.Errorf("unparsed scan status = %s, want inserted", results[0].Status)
+	}
+
+	if results[1].Status != entities.ScanRejected {
+		t.Errorf("overlong scan status = %s, want rejected", results[1].Status)
+	}
+
+	if len(repo.synced) != 1 || repo.synced[0].StudentId != "" {
+		t.Errorf("synced = %+v, want the unparsed scan without student fields", repo.synced)
+	}
+}
+
+func TestSyncParticipantsRecordsApiKey(t *testing.T) {
+	s, repo := newTestParticipantService()
+
+	apiKeyId := uuid.New()
+	_, err := s.SyncParticipants(uuid.NewString(), &entities.ScanActor{DeviceId: "kiosk", ApiKeyId: &apiKeyId}, &requests.SyncParticipants{
+		Scans: []requests.SyncScan{
+			{ScanId: uuid.NewString(), Barcode: "64105000018", Timestamp: "2026-10-18T10:00:00Z"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.synced) != 1 {
+		t.Fatalf("synced %d participants, want 1", len(repo.synced))
+	}
+
+	if repo.synced[0].ApiKeyId == nil || *repo.synced[0].ApiKeyId != apiKeyId {
+		t.Errorf("api key = %v, want %s", repo.synced[0].ApiKeyId, apiKeyId)
+	}
+}
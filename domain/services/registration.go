@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/google/uuid"
+)
+
+type RegistrationService interface {
+	Create(eventId string, actor *entities.Actor, r *requests.RegistrationRequest) (int64, error)
+	Import(eventId string, actor *entities.Actor, filename string, rows [][]string) (int64, error)
+	GetPagination(eventId string, search string, pageIndex string, pageSize string) ([]entities.Registration, error)
+	Count(eventId string, search string) (int64, error)
+	Delete(eventId string, actor *entities.Actor, studentId string) error
+	GetSummary(eventId string) (*entities.RegistrationSummary, error)
+}
+
+type registrationService struct {
+	repo  repositories.RegistrationRepository
+	audit AuditService
+}
+
+func NewRegistrationService(repo repositories.RegistrationRepository, audit AuditService) RegistrationService {
+	return &registrationService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+func isStudentId(studentId string) bool {
+	return len(studentId) == 10 && strings.Trim(studentId, "0123456789") == ""
+}
+
+func (s *registrationService) Create(eventId string, actor *entities.Actor, r *requests.RegistrationRequest) (int64, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return 0, nerrors.ErrCannotParseUUID
+	}
+
+	count, err := s.repo.Create(parsedId, r.StudentIds, actor.Email)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(s.audit, actor, "registration.create", "event", eventId, nil, r)
+
+	return count, nil
+}
+
+// Import registers students from spreadsheet rows. The first row is a header
+// naming the studentId column, other columns are ignored.
+func (s *registrationService) Import(eventId string, actor *entities.Actor, filename string, rows [][]string) (int64, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return 0, nerrors.ErrCannotParseUUID
+	}
+
+	if len(rows) < 2 {
+		return 0, nerrors.ErrInvalidRegistrationList
+	}
+
+	column := -1
+	for i, header := range rows[0] {
+		if rosterColumns[normalizeHeader(header)] == "studentId" {
+			column = i
+			break
+		}
+	}
+
+	if column == -1 {
+		return 0, fmt.Errorf("%w: missing studentId column", nerrors.ErrInvalidRegistrationList)
+	}
+
+	studentIds := make([]string, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		if column >= len(row) {
+			continue
+		}
+
+		studentId := strings.TrimSpace(row[column])
+		if studentId == "" {
+			continue
+		}
+
+		if !isStudentId(studentId) {
+			return 0, fmt.Errorf("%w: row %d studentId must be 10 digits", nerrors.ErrInvalidRegistrationList, i+2)
+		}
+
+		studentIds = append(studentIds, studentId)
+	}
+
+	count, err := s.repo.Create(parsedId, studentIds, actor.Email)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(s.audit, actor, "registration.import", "event", eventId, nil, map[string]any{
+		"file":       filename,
+		"registered": count,
+	})
+
+	return count, nil
+}
+
+func (s *registrationService) GetPagination(eventId string, search string, pageIndex string, pageSize string) ([]entities.Registration, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	parsedIndex, err := strconv.ParseInt(pageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(pageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.repo.GetPagination(parsedId, search, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if registrations == nil {
+		return []entities.Registration{}, nil
+	}
+
+	return registrations, nil
+}
+
+func (s *registrationService) Count(eventId string, search string) (int64, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return 0, nerrors.ErrCannotParseUUID
+	}
+
+	return s.repo.Count(parsedId, search)
+}
+
+func (s *registrationService) Delete(eventId string, actor *entities.Actor, studentId string) error {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nerrors.ErrCannotParseUUID
+	}
+
+	err = s.repo.Delete(parsedId, studentId)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "registration.delete", "event", eventId, map[string]any{
+		"studentId": studentId,
+	}, nil)
+
+	return nil
+}
+
+func (s *registrationService) GetSummary(eventId string) (*entities.RegistrationSummary, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	return s.repo.GetSummary(parsedId)
+}
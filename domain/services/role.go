@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+)
+
+// ResolveRole returns the role email currently holds, admin before staff. It
+// returns ErrUserNotFound when they are neither.
+func ResolveRole(adminService AdminService, staffService StaffService, email string) (*string, error) {
+	admin, err := adminService.GetByEmail(email)
+	if err != nil {
+		if !errors.Is(err, nerrors.ErrAdminNotFound) {
+			return nil, err
+		}
+	}
+
+	var role string
+
+	if admin != nil {
+		role = "admin"
+		return &role, nil
+	}
+
+	staffs, err := staffService.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(staffs) > 0 {
+		role = "staff"
+		return &role, nil
+	}
+
+	return nil, nerrors.ErrUserNotFound
+}
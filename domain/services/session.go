@@ -0,0 +1,202 @@
+package services
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/google/uuid"
+)
+
+type SessionService interface {
+	GetAllFromEventId(eventId string) ([]*entities.Session, error)
+	GetById(eventId string, id string) (*entities.Session, error)
+	Create(eventId string, actor *entities.Actor, r *requests.SessionRequest) (*entities.Session, error)
+	UpdateById(eventId string, id string, actor *entities.Actor, r *requests.SessionRequest) error
+	DeleteById(eventId string, id string, actor *entities.Actor) error
+	GetAttendance(eventId string, search string, pageIndex string, pageSize string) ([]entities.SessionAttendance, error)
+	CountAttendance(eventId string, search string) (int64, error)
+}
+
+type sessionService struct {
+	repo  repositories.SessionRepository
+	audit AuditService
+}
+
+func NewSessionService(repo repositories.SessionRepository, audit AuditService) SessionService {
+	return &sessionService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+func parseSessionRequest(eventId uuid.UUID, r *requests.SessionRequest) (*entities.Session, error) {
+	startAt, err := time.Parse(time.RFC3339, r.StartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	endAt, err := time.Parse(time.RFC3339, r.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if endAt.Before(startAt) {
+		return nil, nerrors.ErrInvalidSessionWindow
+	}
+
+	return &entities.Session{
+		EventId: eventId,
+		Name:    r.Name,
+		StartAt: startAt.UTC(),
+		EndAt:   endAt.UTC(),
+	}, nil
+}
+
+func (s *sessionService) GetAllFromEventId(eventId string) ([]*entities.Session, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	sessions, err := s.repo.GetAllFromEvent(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if sessions == nil {
+		return []*entities.Session{}, nil
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) GetById(eventId string, id string) (*entities.Session, error) {
+	parsedEventId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	session, err := s.repo.GetById(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.EventId != parsedEventId {
+		return nil, nerrors.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (s *sessionService) Create(eventId string, actor *entities.Actor, r *requests.SessionRequest) (*entities.Session, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	session, err := parseSessionRequest(parsedId, r)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(session)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.audit, actor, "session.create", "session", created.Id.String(), nil, created)
+
+	return created, nil
+}
+
+func (s *sessionService) UpdateById(eventId string, id string, actor *entities.Actor, r *requests.SessionRequest) error {
+	record, err := s.GetById(eventId, id)
+	if err != nil {
+		return err
+	}
+
+	session, err := parseSessionRequest(record.EventId, r)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UpdateById(record.Id, session)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "session.update", "session", id, record, r)
+
+	return nil
+}
+
+func (s *sessionService) DeleteById(eventId string, id string, actor *entities.Actor) error {
+	record, err := s.GetById(eventId, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteById(record.Id)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "session.delete", "session", id, record, nil)
+
+	return nil
+}
+
+func (s *sessionService) GetAttendance(eventId string, search string, pageIndex string, pageSize string) ([]entities.SessionAttendance, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return nil, nerrors.ErrCannotParseUUID
+	}
+
+	parsedIndex, err := strconv.ParseInt(pageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(pageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	totalSessions, err := s.repo.CountFromEvent(parsedId)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance, err := s.repo.GetAttendance(parsedId, search, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if attendance == nil {
+		return []entities.SessionAttendance{}, nil
+	}
+
+	for i := range attendance {
+		attendance[i].TotalSessions = totalSessions
+	}
+
+	return attendance, nil
+}
+
+func (s *sessionService) CountAttendance(eventId string, search string) (int64, error) {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return 0, nerrors.ErrCannotParseUUID
+	}
+
+	return s.repo.CountAttendance(parsedId, search)
+}
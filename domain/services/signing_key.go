@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/go-jose/go-jose/v4"
+)
+
+type SigningKeyService interface {
+	Init() error
+	Reload() error
+	Rotate() error
+	Run(ctx context.Context)
+	JWKS() jose.JSONWebKeySet
+}
+
+type signingKeyService struct {
+	repo     repositories.SigningKeyRepository
+	rotation time.Duration
+}
+
+// NewSigningKeyService rotates the signing key every rotation. A retired key
+// is kept for verification until every token it signed has expired.
+func NewSigningKeyService(repo repositories.SigningKeyRepository, rotation time.Duration) SigningKeyService {
+	return &signingKeyService{
+		repo:     repo,
+		rotation: rotation,
+	}
+}
+
+// Init loads the active keys, creating the first one on a fresh database.
+func (s *signingKeyService) Init() error {
+	libs.Keys.SetReload(s.Reload)
+
+	return s.rotateIfDue()
+}
+
+func (s *signingKeyService) Reload() error {
+	keys, err := s.repo.GetActive()
+	if err != nil {
+		return err
+	}
+
+	return libs.Keys.Load(keys)
+}
+
+// Rotate creates a new key. It is published right away and starts signing
+// after libs.KeyPublishAhead, once JWKS copies cached by verifiers include
+// it. Tokens signed with the previous key keep verifying until it expires.
+func (s *signingKeyService) Rotate() error {
+	return s.create(libs.KeyPublishAhead)
+}
+
+func (s *signingKeyService) create(publishAhead time.Duration) error {
+	key, err := libs.GenerateSigningKey()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key.CreatedAt = now
+	key.ActiveFrom = now.Add(publishAhead)
+	key.ExpiresAt = key.ActiveFrom.Add(s.rotation + libs.RefreshTokenLifetime)
+
+	err = s.repo.Create(key)
+	if err != nil {
+		return err
+	}
+
+	return s.Reload()
+}
+
+func (s *signingKeyService) rotateIfDue() error {
+	keys, err := s.repo.GetActive()
+	if err != nil {
+		return err
+	}
+
+	// Nothing can have cached the JWKS of a fresh database, so the first key
+	// signs right away
+	if len(keys) == 0 {
+		return s.create(0)
+	}
+
+	// The next key is created early enough to become active once the
+	// current one has signed for a full rotation
+	if time.Since(keys[0].ActiveFrom) >= s.rotation-libs.KeyPublishAhead {
+		return s.Rotate()
+	}
+
+	return libs.Keys.Load(keys)
+}
+
+// Run rotates the key when it is due and picks up keys created by other
+// instances until ctx is done.
+func (s *signingKeyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.DeleteExpired(); err != nil {
+				log.Println("Cannot delete expired signing keys: ", err)
+			}
+
+			if err := s.rotateIfDue(); err != nil {
+				log.Println("Cannot rotate signing key: ", err)
+			}
+		}
+	}
+}
+
+func (s *signingKeyService) JWKS() jose.JSONWebKeySet {
+	return libs.Keys.JWKS()
+}
@@ -1,41 +1,143 @@
 package services
 
 import (
+	"errors"
+
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
 	"github.com/google/uuid"
 )
 
 type staffService struct {
-	repo repositories.StaffRepository
+	repo      repositories.StaffRepository
+	tokenRepo repositories.TokenRepository
+	audit     AuditService
 }
 
 type StaffService interface {
-	SetStaffs(emails []string, eventId string) error
+	SetStaffs(eventId string, actor *entities.Actor, r *requests.SetStaffRequest) error
+	AddStaff(eventId string, actor *entities.Actor, staff *entities.Staff) error
 	GetAllFromEventId(id string) ([]*entities.Staff, error)
 	GetByEmail(email string) ([]entities.Staff, error)
 	GetByEmailAndEventId(email string, eventId string) (*entities.Staff, error)
+	HasRole(email string, eventId string, role entities.StaffRole) (bool, error)
 }
 
-func NewStaffService(repo repositories.StaffRepository) StaffService {
+func NewStaffService(repo repositories.StaffRepository, tokenRepo repositories.TokenRepository, audit AuditService) StaffService {
 	return &staffService{
-		repo: repo,
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		audit:     audit,
+	}
+}
+
+func (s *staffService) SetStaffs(eventId string, actor *entities.Actor, r *requests.SetStaffRequest) error {
+	parsedId, err := uuid.Parse(eventId)
+	if err != nil {
+		return err
+	}
+
+	staffs, err := mergeStaffs(r)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.repo.GetAllFromEvent(&parsedId)
+	if err != nil && !errors.Is(err, nerrors.ErrStaffNotFound) {
+		return err
+	}
+
+	err = s.repo.ReplaceAll(staffs, parsedId)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "staff.set", "event", eventId, previous, r)
+
+	return s.revokeRemoved(previous, staffs)
+}
+
+// mergeStaffs lists each email of r once. An email in both lists takes the
+// role given in Staffs over the supervisor role of the legacy Email list.
+func mergeStaffs(r *requests.SetStaffRequest) ([]entities.Staff, error) {
+	staffs := make([]entities.Staff, 0, len(r.Email)+len(r.Staffs))
+	index := make(map[string]int, cap(staffs))
+
+	add := func(staff entities.Staff) {
+		if i, ok := index[staff.Email]; ok {
+			staffs[i] = staff
+			return
+		}
+		index[staff.Email] = len(staffs)
+		staffs = append(staffs, staff)
+	}
+
+	for _, email := range r.Email {
+		add(entities.Staff{
+			Email: email,
+			Role:  entities.StaffSupervisor,
+		})
+	}
+
+	for _, staff := range r.Staffs {
+		role := entities.StaffRole(staff.Role)
+		if !role.IsValid() {
+			return nil, nerrors.ErrInvalidStaffRole
+		}
+
+		add(entities.Staff{
+			Email: staff.Email,
+			Role:  role,
+		})
 	}
+
+	return staffs, nil
 }
 
-func (s *staffService) SetStaffs(emails []string, eventId string) error {
+// revokeRemoved invalidates the access tokens of staff that are no longer on
+// the event, so they cannot keep a staff role they may no longer hold.
+func (s *staffService) revokeRemoved(previous []*entities.Staff, current []entities.Staff) error {
+	kept := make(map[string]bool, len(current))
+	for _, staff := range current {
+		kept[staff.Email] = true
+	}
+
+	for _, staff := range previous {
+		if kept[staff.Email] {
+			continue
+		}
+
+		err := s.tokenRepo.BumpTokenVersion(staff.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddStaff adds one staff member to the event, or changes their role if they
+// are already on it.
+func (s *staffService) AddStaff(eventId string, actor *entities.Actor, staff *entities.Staff) error {
 	parsedId, err := uuid.Parse(eventId)
 	if err != nil {
 		return err
 	}
 
-	err = s.repo.DeleteAll(parsedId)
+	if !staff.Role.IsValid() {
+		return nerrors.ErrInvalidStaffRole
+	}
+
+	err = s.repo.Upsert(staff, parsedId)
 	if err != nil {
 		return err
 	}
 
-	err = s.repo.AddStaffs(emails, parsedId)
-	return err
+	recordAudit(s.audit, actor, "staff.add", "event", eventId, nil, staff)
+
+	return nil
 }
 
 func (s *staffService) GetAllFromEventId(id string) ([]*entities.Staff, error) {
@@ -69,3 +171,16 @@ func (s *staffService) GetByEmailAndEventId(email string, eventId string) (*enti
 
 	return staff, nil
 }
+
+// HasRole reports whether email is staff of the event with at least role.
+func (s *staffService) HasRole(email string, eventId string, role entities.StaffRole) (bool, error) {
+	staff, err := s.GetByEmailAndEventId(email, eventId)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrStaffNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return staff.Role.Includes(role), nil
+}
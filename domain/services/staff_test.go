@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/google/uuid"
+)
+
+type fakeStaffRepo struct {
+	repositories.StaffRepository
+	staffs   []*entities.Staff
+	replaced int
+}
+
+func (r *fakeStaffRepo) GetAllFromEvent(id *uuid.UUID) ([]*entities.Staff, error) {
+	return r.staffs, nil
+}
+
+func (r *fakeStaffRepo) ReplaceAll(staffs []entities.Staff, eventId uuid.UUID) error {
+	r.replaced++
+	r.staffs = r.staffs[:0]
+	for _, staff := range staffs {
+		r.staffs = append(r.staffs, &staff)
+	}
+	return nil
+}
+
+func TestSetStaffsMergesDuplicateEmails(t *testing.T) {
+	repo := &fakeStaffRepo{}
+	s := NewStaffService(repo, newFakeTokenRepo(), NewAuditService(&fakeAuditRepo{}))
+
+	err := s.SetStaffs(uuid.NewString(), &entities.Actor{}, &requests.SetStaffRequest{
+		Email: []string{"a@ku.th", "b@ku.th"},
+		Staffs: []requests.StaffRequest{
+			{Email: "a@ku.th", Role: "scanner"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.staffs) != 2 {
+		t.Fatalf("staffs = %d, want 2", len(repo.staffs))
+	}
+
+	if repo.staffs[0].Email != "a@ku.th" || repo.staffs[0].Role != entities.StaffScanner {
+		t.Errorf("staffs[0] = %+v, want a@ku.th as scanner", *repo.staffs[0])
+	}
+
+	if repo.staffs[1].Email != "b@ku.th" || repo.staffs[1].Role != entities.StaffSupervisor {
+		t.Errorf("staffs[1] = %+v, want b@ku.th as supervisor", *repo.staffs[1])
+	}
+}
+
+func TestSetStaffsRejectsInvalidRoleBeforeWriting(t *testing.T) {
+	repo := &fakeStaffRepo{
+		staffs: []*entities.Staff{{Email: "a@ku.th", Role: entities.StaffSupervisor}},
+	}
+	s := NewStaffService(repo, newFakeTokenRepo(), NewAuditService(&fakeAuditRepo{}))
+
+	err := s.SetStaffs(uuid.NewString(), &entities.Actor{}, &requests.SetStaffRequest{
+		Staffs: []requests.StaffRequest{
+			{Email: "b@ku.th", Role: "owner"},
+		},
+	})
+	if !errors.Is(err, nerrors.ErrInvalidStaffRole) {
+		t.Errorf("err = %v, want ErrInvalidStaffRole", err)
+	}
+
+	if repo.replaced != 0 || len(repo.staffs) != 1 {
+		t.Errorf("staff were replaced %d times, want the previous staff kept", repo.replaced)
+	}
+}
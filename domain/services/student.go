@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+)
+
+type StudentService interface {
+	Import(actor *entities.Actor, filename string, rows [][]string) (int, error)
+	GetById(studentId string) (*entities.Student, error)
+	GetPagination(search string, pageIndex string, pageSize string) ([]entities.Student, error)
+	Count(search string) (int64, error)
+	GetAttendances(barcode string, from string, to string, pageIndex string, pageSize string) ([]entities.Attendance, error)
+	GetAttendanceSummary(barcode string, from string, to string) (*entities.AttendanceSummary, error)
+}
+
+type studentService struct {
+	repo            repositories.StudentRepository
+	participantRepo repositories.ParticipantRepository
+	audit           AuditService
+}
+
+func NewStudentService(repo repositories.StudentRepository, participantRepo repositories.ParticipantRepository, audit AuditService) StudentService {
+	return &studentService{
+		repo:            repo,
+		participantRepo: participantRepo,
+		audit:           audit,
+	}
+}
+
+var rosterColumns = map[string]string{
+	"studentid": "studentId",
+	"id":        "studentId",
+	"fullname":  "fullName",
+	"name":      "fullName",
+	"faculty":   "faculty",
+	"major":     "major",
+}
+
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	header = strings.ReplaceAll(header, "_", "")
+	return strings.ReplaceAll(header, " ", "")
+}
+
+// Import upserts students from spreadsheet rows. The first row is a header
+// naming the studentId, fullName, faculty and major columns in any order.
+func (s *studentService) Import(actor *entities.Actor, filename string, rows [][]string) (int, error) {
+	if len(rows) < 2 {
+		return 0, nerrors.ErrInvalidStudentRoster
+	}
+
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		if column, ok := rosterColumns[normalizeHeader(header)]; ok {
+			columns[column] = i
+		}
+	}
+
+	for _, column := range []string{"studentId", "fullName", "faculty", "major"} {
+		if _, ok := columns[column]; !ok {
+			return 0, fmt.Errorf("%w: missing %s column", nerrors.ErrInvalidStudentRoster, column)
+		}
+	}
+
+	cell := func(row []string, column string) string {
+		i := columns[column]
+		if i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	students := make([]entities.Student, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		r := requests.StudentRequest{
+			StudentId: cell(row, "studentId"),
+			FullName:  cell(row, "fullName"),
+			Faculty:   cell(row, "faculty"),
+			Major:     cell(row, "major"),
+		}
+
+		if r == (requests.StudentRequest{}) {
+			continue
+		}
+
+		if errs := libs.Validator.Validate(r); errs != nil {
+			return 0, fmt.Errorf("%w: row %d %s", nerrors.ErrInvalidStudentRoster, i+2, strings.Join(errs.Errors, ", "))
+		}
+
+		students = append(students, entities.Student{
+			StudentId: r.StudentId,
+			FullName:  r.FullName,
+			Faculty:   r.Faculty,
+			Major:     r.Major,
+		})
+	}
+
+	err := s.repo.Upsert(students)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(s.audit, actor, "student.import", "student", filename, nil, map[string]any{
+		"imported": len(students),
+	})
+
+	return len(students), nil
+}
+
+func (s *studentService) GetById(studentId string) (*entities.Student, error) {
+	return s.repo.GetById(studentId)
+}
+
+func (s *studentService) GetPagination(search string, pageIndex string, pageSize string) ([]entities.Student, error) {
+	parsedIndex, err := strconv.ParseInt(pageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(pageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.repo.GetPagination(search, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if students == nil {
+		return []entities.Student{}, nil
+	}
+
+	return students, nil
+}
+
+func (s *studentService) Count(search string) (int64, error) {
+	return s.repo.Count(search)
+}
+
+const dateLayout = "2006-01-02"
+
+// parseDateRange parses optional YYYY-MM-DD bounds, an empty bound is left
+// open.
+func parseDateRange(from string, to string) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+
+	if from != "" {
+		parsed, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return nil, nil, nerrors.ErrInvalidDateRange
+		}
+		start = &parsed
+	}
+
+	if to != "" {
+		parsed, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return nil, nil, nerrors.ErrInvalidDateRange
+		}
+		end = &parsed
+	}
+
+	if start != nil && end != nil && end.Before(*start) {
+		return nil, nil, nerrors.ErrInvalidDateRange
+	}
+
+	return start, end, nil
+}
+
+func (s *studentService) GetAttendances(barcode string, from string, to string, pageIndex string, pageSize string) ([]entities.Attendance, error) {
+	start, end, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedIndex, err := strconv.ParseInt(pageIndex, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSize, err := strconv.ParseInt(pageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	attendances, err := s.participantRepo.GetAttendances(barcode, start, end, int32(parsedIndex), int32(parsedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if attendances == nil {
+		return []entities.Attendance{}, nil
+	}
+
+	for i, attendance := range attendances {
+		if attendance.CheckedOutAt == nil {
+			continue
+		}
+
+		dwell := int64(attendance.CheckedOutAt.Sub(attendance.Timestamp).Seconds())
+		attendances[i].DwellSeconds = &dwell
+	}
+
+	return attendances, nil
+}
+
+func (s *studentService) GetAttendanceSummary(barcode string, from string, to string) (*entities.AttendanceSummary, error) {
+	start, end, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.participantRepo.GetAttendanceSummary(barcode, start, end)
+}
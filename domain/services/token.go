@@ -1,96 +1,274 @@
 package services
 
 import (
+	"errors"
 	"time"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
 	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/google/uuid"
 )
 
 type TokenService interface {
-	GetToken(email string) (*entities.RefreshToken, error)
-	AddRefreshToken(email string, token string) error
-	RemoveToken(email string) error
-	RefreshToken(accessToken string, refreshToken string) (*AuthToken, error)
+	IssueTokens(sessionId string, email string, name string, picture string, role string) (*AuthToken, error)
+	GetTokenVersion(email string) (int32, error)
+	RevokeAccessTokens(email string) error
+	AddRefreshToken(token *entities.RefreshToken, refreshToken string) error
+	GetSessions(email string, currentId string) ([]entities.RefreshToken, error)
+	RevokeSession(email string, id string) error
+	RevokeAllSessions(email string) error
+	ForceLogout(actor *entities.Actor, email string) error
+	RevokeRefreshToken(refreshToken string) error
+	RefreshToken(accessToken string, refreshToken string, userAgent string, ip string) (*AuthToken, error)
 }
 
 type tokenService struct {
-	repo repositories.TokenRepository
+	repo         repositories.TokenRepository
+	adminService AdminService
+	staffService StaffService
+	audit        AuditService
 }
 
-func NewTokenService(r repositories.TokenRepository) TokenService {
+func NewTokenService(r repositories.TokenRepository, adminService AdminService, staffService StaffService, audit AuditService) TokenService {
 	return &tokenService{
-		repo: r,
+		repo:         r,
+		adminService: adminService,
+		staffService: staffService,
+		audit:        audit,
 	}
 }
 
-func (s *tokenService) GetToken(email string) (*entities.RefreshToken, error) {
-	return s.repo.GetRefreshToken(email)
+// IssueTokens signs an access and refresh token pair for a session. The
+// access token carries the current token version of email.
+func (s *tokenService) IssueTokens(sessionId string, email string, name string, picture string, role string) (*AuthToken, error) {
+	version, err := s.repo.GetTokenVersion(email)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, accessExp, err := libs.GenerateAccessToken(sessionId, version, email, name, picture, role)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExp, err := libs.GenerateRefreshToken(sessionId, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthToken{
+		SessionId:           sessionId,
+		AccessToken:         *accessToken,
+		AccessTokenExpired:  *accessExp,
+		RefreshToken:        *refreshToken,
+		RefreshTokenExpired: *refreshExp,
+	}, nil
 }
 
-func (s *tokenService) AddRefreshToken(email string, token string) error {
-	return s.repo.AddRefreshToken(email, token)
+func (s *tokenService) GetTokenVersion(email string) (int32, error) {
+	return s.repo.GetTokenVersion(email)
 }
 
-func (s *tokenService) RemoveToken(email string) error {
-	return s.repo.RemoveRefreshToken(email)
+// RevokeAccessTokens invalidates every access token issued to email so far.
+// Sessions that are still valid pick up a new one on their next refresh.
+func (s *tokenService) RevokeAccessTokens(email string) error {
+	return s.repo.BumpTokenVersion(email)
 }
 
-func (s *tokenService) RefreshToken(accessToken string, refreshToken string) (*AuthToken, error) {
+// AddRefreshToken starts a new token family for a sign-in. Only the hash of
+// refreshToken is stored.
+func (s *tokenService) AddRefreshToken(token *entities.RefreshToken, refreshToken string) error {
+	token.TokenHash = libs.HashToken(refreshToken)
+	return s.repo.AddRefreshToken(token)
+}
 
-	refreshClaims, err := libs.ParseJwt(refreshToken)
+// GetSessions lists the devices email is signed in on. The one matching
+// currentId is flagged as the current session.
+func (s *tokenService) GetSessions(email string, currentId string) ([]entities.RefreshToken, error) {
+	sessions, err := s.repo.GetRefreshTokensByEmail(email)
 	if err != nil {
 		return nil, err
 	}
 
-	accessClaims, err := libs.ParseJwt(accessToken)
+	if sessions == nil {
+		return []entities.RefreshToken{}, nil
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id.String() == currentId
+	}
+
+	return sessions, nil
+}
+
+func (s *tokenService) RevokeSession(email string, id string) error {
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return nerrors.ErrTokenNotFound
+	}
+
+	err = s.repo.RemoveRefreshToken(parsedId, email)
+	if err != nil {
+		return err
+	}
+
+	return s.RevokeAccessTokens(email)
+}
+
+func (s *tokenService) RevokeAllSessions(email string) error {
+	err := s.repo.RemoveRefreshTokensByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	return s.RevokeAccessTokens(email)
+}
+
+// ForceLogout signs another user out of every session on behalf of actor.
+func (s *tokenService) ForceLogout(actor *entities.Actor, email string) error {
+	err := s.RevokeAllSessions(email)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(s.audit, actor, "user.force_logout", "user", email, nil, nil)
+
+	return nil
+}
+
+// RevokeRefreshToken ends the session refreshToken belongs to.
+func (s *tokenService) RevokeRefreshToken(refreshToken string) error {
+	claims, err := libs.ParseJwt(refreshToken, libs.TokenTypeRefresh)
+	if err != nil {
+		return err
+	}
+
+	sessionId, ok := claims["sid"].(string)
+	if !ok {
+		return nerrors.ErrTokenNotValid
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		return nerrors.ErrTokenNotValid
+	}
+
+	return s.RevokeSession(email, sessionId)
+}
+
+// revokeFamily ends the session of a replayed refresh token. The token
+// version is bumped as well, so an access token already issued from the
+// stolen chain stops working right away instead of at its expiry.
+func (s *tokenService) revokeFamily(record *entities.RefreshToken) error {
+	err := s.repo.RemoveRefreshToken(record.Id, record.Email)
+	if err != nil && !errors.Is(err, nerrors.ErrTokenNotFound) {
+		return err
+	}
+
+	err = s.repo.BumpTokenVersion(record.Email)
+	if err != nil {
+		return err
+	}
+
+	return nerrors.ErrTokenReused
+}
+
+func (s *tokenService) RefreshToken(accessToken string, refreshToken string, userAgent string, ip string) (*AuthToken, error) {
+
+	refreshClaims, err := libs.ParseJwt(refreshToken, libs.TokenTypeRefresh)
 	if err != nil {
 		return nil, err
 	}
 
-	exp := time.Unix(int64(accessClaims["exp"].(float64)), 0)
+	accessClaims, err := libs.ParseJwt(accessToken, libs.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
 
-	if exp.Sub(time.Now()).Minutes() > 5 {
-		return nil, nerrors.ErrTokenStillValid
+	email, ok := accessClaims["email"].(string)
+	if !ok {
+		return nil, nerrors.ErrTokenNotValid
 	}
 
-	record, err := s.GetToken(refreshClaims["email"].(string))
+	name, _ := accessClaims["name"].(string)
+	picture, _ := accessClaims["picture"].(string)
+
+	currentVersion, err := s.repo.GetTokenVersion(email)
 	if err != nil {
 		return nil, err
 	}
 
-	if record.Token != refreshToken {
-		return nil, nerrors.ErrTokenNotMatch
+	// A revoked access token is refreshed right away instead of waiting for
+	// it to get close to expiry
+	version, _ := accessClaims["ver"].(float64)
+	expUnix, ok := accessClaims["exp"].(float64)
+	if !ok {
+		return nil, nerrors.ErrTokenNotValid
+	}
+	exp := time.Unix(int64(expUnix), 0)
+
+	if int32(version) == currentVersion && exp.Sub(time.Now()).Minutes() > 5 {
+		return nil, nerrors.ErrTokenStillValid
+	}
+
+	sessionId, ok := refreshClaims["sid"].(string)
+	if !ok {
+		return nil, nerrors.ErrTokenNotValid
+	}
+
+	parsedId, err := uuid.Parse(sessionId)
+	if err != nil {
+		return nil, nerrors.ErrTokenNotValid
 	}
 
-	err = s.RemoveToken(refreshClaims["email"].(string))
+	record, err := s.repo.GetRefreshToken(parsedId)
 	if err != nil {
 		return nil, err
 	}
 
-	email := accessClaims["email"].(string)
+	if record.Email != email {
+		return nil, nerrors.ErrTokenNotMatch
+	}
+
+	// A validly signed token that is no longer the latest of its family has
+	// already been rotated, so someone is replaying it. Revoke the family to
+	// sign out both the attacker and the victim.
+	previousHash := libs.HashToken(refreshToken)
+	if record.TokenHash != previousHash {
+		return nil, s.revokeFamily(record)
+	}
 
-	newAccessToken, accessExp, err := libs.GenerateAccessToken(email, accessClaims["name"].(string), accessClaims["picture"].(string), accessClaims["role"].(string))
+	// The role may have changed since the last refresh, e.g. an admin was
+	// removed, so it is looked up again rather than copied from the claims
+	role, err := ResolveRole(s.adminService, s.staffService, email)
 	if err != nil {
+		if errors.Is(err, nerrors.ErrUserNotFound) {
+			removeErr := s.repo.RemoveRefreshToken(record.Id, record.Email)
+			if removeErr != nil && !errors.Is(removeErr, nerrors.ErrTokenNotFound) {
+				return nil, removeErr
+			}
+		}
 		return nil, err
 	}
 
-	newRefreshToken, refreshExp, err := libs.GenerateRefreshToken(accessClaims["email"].(string))
+	token, err := s.IssueTokens(sessionId, email, name, picture, *role)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.AddRefreshToken(email, *newRefreshToken)
+	record.TokenHash = libs.HashToken(token.RefreshToken)
+	record.UserAgent = userAgent
+	record.Ip = ip
+
+	err = s.repo.RotateRefreshToken(record, previousHash)
 	if err != nil {
+		if errors.Is(err, nerrors.ErrTokenNotMatch) {
+			return nil, s.revokeFamily(record)
+		}
 		return nil, err
 	}
 
-	return &AuthToken{
-		AccessToken:         *newAccessToken,
-		AccessTokenExpired:  *accessExp,
-		RefreshToken:        *newRefreshToken,
-		RefreshTokenExpired: *refreshExp,
-	}, nil
+	return token, nil
 }
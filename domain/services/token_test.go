@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/google/uuid"
+)
+
+type fakeTokenRepo struct {
+	tokens   map[uuid.UUID]entities.RefreshToken
+	versions map[string]int32
+	// beforeRotate runs inside RotateRefreshToken, before the compare and
+	// swap, to simulate a concurrent refresh.
+	beforeRotate func()
+}
+
+func newFakeTokenRepo() *fakeTokenRepo {
+	return &fakeTokenRepo{
+		tokens:   map[uuid.UUID]entities.RefreshToken{},
+		versions: map[string]int32{},
+	}
+}
+
+func (r *fakeTokenRepo) GetRefreshToken(id uuid.UUID) (*entities.RefreshToken, error) {
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, nerrors.ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+func (r *fakeTokenRepo) GetRefreshTokensByEmail(email string) ([]entities.RefreshToken, error) {
+	var tokens []entities.RefreshToken
+	for _, token := range r.tokens {
+		if token.Email == email {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeTokenRepo) AddRefreshToken(token *entities.RefreshToken) error {
+	r.tokens[token.Id] = *token
+	return nil
+}
+
+func (r *fakeTokenRepo) RotateRefreshToken(token *entities.RefreshToken, previousHash string) error {
+	if r.beforeRotate != nil {
+		r.beforeRotate()
+	}
+
+	current, ok := r.tokens[token.Id]
+	if !ok || current.TokenHash != previousHash {
+		return nerrors.ErrTokenNotMatch
+	}
+
+	r.tokens[token.Id] = *token
+	return nil
+}
+
+func (r *fakeTokenRepo) RemoveRefreshToken(id uuid.UUID, email string) error {
+	token, ok := r.tokens[id]
+	if !ok || token.Email != email {
+		return nerrors.ErrTokenNotFound
+	}
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *fakeTokenRepo) RemoveRefreshTokensByEmail(email string) error {
+	for id, token := range r.tokens {
+		if token.Email == email {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepo) GetTokenVersion(email string) (int32, error) {
+	return r.versions[email], nil
+}
+
+func (r *fakeTokenRepo) BumpTokenVersion(email string) error {
+	r.versions[email]++
+	return nil
+}
+
+type fakeAdminService struct {
+	AdminService
+	admins map[string]bool
+}
+
+func (s *fakeAdminService) GetByEmail(email string) (*entities.Admin, error) {
+	if !s.admins[email] {
+		return nil, nerrors.ErrAdminNotFound
+	}
+	return &entities.Admin{Email: email}, nil
+}
+
+type fakeStaffService struct {
+	StaffService
+}
+
+func (s *fakeStaffService) GetByEmail(email string) ([]entities.Staff, error) {
+	return nil, nil
+}
+
+const testEmail = "admin@example.com"
+
+func useTestSigningKey(t *testing.T) {
+	t.Helper()
+
+	key, err := libs.GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = libs.Keys.Load([]entities.SigningKey{*key})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// signIn starts a session like the OAuth callback does and returns its
+// tokens. The token version is bumped afterwards so the access token is due
+// for a refresh.
+func signIn(t *testing.T, s *tokenService, repo *fakeTokenRepo) *AuthToken {
+	t.Helper()
+
+	sessionId := uuid.New()
+	token, err := s.IssueTokens(sessionId.String(), testEmail, "Admin", "", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.AddRefreshToken(&entities.RefreshToken{
+		Id:    sessionId,
+		Email: testEmail,
+	}, token.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.versions[testEmail]++
+
+	return token
+}
+
+func newTestTokenService(t *testing.T, admins ...string) (*tokenService, *fakeTokenRepo) {
+	t.Helper()
+	useTestSigningKey(t)
+
+	isAdmin := map[string]bool{}
+	for _, email := range admins {
+		isAdmin[email] = true
+	}
+
+	repo := newFakeTokenRepo()
+	s := &tokenService{
+		repo:         repo,
+		adminService: &fakeAdminService{admins: isAdmin},
+		staffService: &fakeStaffService{},
+	}
+
+	return s, repo
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	s, repo := newTestTokenService(t, testEmail)
+	token := signIn(t, s, repo)
+
+	refreshed, err := s.RefreshToken(token.AccessToken, token.RefreshToken, "agent", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refreshed.SessionId != token.SessionId {
+		t.Errorf("SessionId = %s, want %s", refreshed.SessionId, token.SessionId)
+	}
+
+	record := repo.tokens[uuid.MustParse(token.SessionId)]
+	if record.TokenHash != libs.HashToken(refreshed.RefreshToken) {
+		t.Error("stored hash is not the hash of the new refresh token")
+	}
+
+	if record.UserAgent != "agent" || record.Ip != "127.0.0.1" {
+		t.Errorf("record = %+v, want the new user agent and ip", record)
+	}
+
+	claims, err := libs.ParseJwt(refreshed.AccessToken, libs.TokenTypeAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version, _ := claims["ver"].(float64); int32(version) != repo.versions[testEmail] {
+		t.Errorf("ver = %v, want %d", claims["ver"], repo.versions[testEmail])
+	}
+}
+
+func TestRefreshTokenStillValid(t *testing.T) {
+	s, repo := newTestTokenService(t, testEmail)
+	token := signIn(t, s, repo)
+	repo.versions[testEmail]--
+
+	_, err := s.RefreshToken(token.AccessToken, token.RefreshToken, "agent", "127.0.0.1")
+	if !errors.Is(err, nerrors.ErrTokenStillValid) {
+		t.Fatalf("err = %v, want ErrTokenStillValid", err)
+	}
+}
+
+func TestRefreshTokenReplayRevokesFamily(t *testing.T) {
+	s, repo := newTestTokenService(t, testEmail)
+	token := signIn(t, s, repo)
+
+	refreshed, err := s.RefreshToken(token.AccessToken, token.RefreshToken, "agent", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := repo.versions[testEmail]
+
+	// Replaying the rotated token is only possible with a stolen copy
+	_, err = s.RefreshToken(token.AccessToken, token.RefreshToken, "attacker", "10.0.0.1")
+	if !errors.Is(err, nerrors.ErrTokenReused) {
+		t.Fatalf("err = %v, want ErrTokenReused", err)
+	}
+
+	if _, ok := repo.tokens[uuid.MustParse(token.SessionId)]; ok {
+		t.Error("token family was not removed")
+	}
+
+	if repo.versions[testEmail] != version+1 {
+		t.Errorf("version = %d, want %d", repo.versions[testEmail], version+1)
+	}
+
+	// The latest token of the family is signed out as well
+	_, err = s.RefreshToken(refreshed.AccessToken, refreshed.RefreshToken, "agent", "127.0.0.1")
+	if !errors.Is(err, nerrors.ErrTokenNotFound) {
+		t.Fatalf("err = %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestRefreshTokenLostRace(t *testing.T) {
+	s, repo := newTestTokenService(t, testEmail)
+	token := signIn(t, s, repo)
+	sessionId := uuid.MustParse(token.SessionId)
+
+	// Another refresh with the same token rotates the family between the
+	// hash check and the compare and swap
+	repo.beforeRotate = func() {
+		record := repo.tokens[sessionId]
+		record.TokenHash = libs.HashToken("rotated elsewhere")
+		repo.tokens[sessionId] = record
+	}
+
+	version := repo.versions[testEmail]
+
+	_, err := s.RefreshToken(token.AccessToken, token.RefreshToken, "agent", "127.0.0.1")
+	if !errors.Is(err, nerrors.ErrTokenReused) {
+		t.Fatalf("err = %v, want ErrTokenReused", err)
+	}
+
+	if _, ok := repo.tokens[sessionId]; ok {
+		t.Error("token family was not removed")
+	}
+
+	if repo.versions[testEmail] != version+1 {
+		t.Errorf("version = %d, want %d", repo.versions[testEmail], version+1)
+	}
+}
+
+func TestRefreshTokenUserRemoved(t *testing.T) {
+	s, repo := newTestTokenService(t)
+	token := signIn(t, s, repo)
+
+	_, err := s.RefreshToken(token.AccessToken, token.RefreshToken, "agent", "127.0.0.1")
+	if !errors.Is(err, nerrors.ErrUserNotFound) {
+		t.Fatalf("err = %v, want ErrUserNotFound", err)
+	}
+
+	if _, ok := repo.tokens[uuid.MustParse(token.SessionId)]; ok {
+		t.Error("session of a removed user was kept")
+	}
+}
+
+func TestRefreshTokenRejectsSwappedTokens(t *testing.T) {
+	s, repo := newTestTokenService(t, testEmail)
+	token := signIn(t, s, repo)
+
+	_, err := s.RefreshToken(token.RefreshToken, token.AccessToken, "agent", "127.0.0.1")
+	if !errors.Is(err, nerrors.ErrTokenNotValid) {
+		t.Fatalf("err = %v, want ErrTokenNotValid", err)
+	}
+}
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type accessRequestHandler struct {
+	app     *fiber.App
+	service services.AccessRequestService
+}
+
+func NewAccessRequestHandler(app *fiber.App, service services.AccessRequestService) {
+	handler := &accessRequestHandler{
+		app:     app,
+		service: service,
+	}
+
+	accessRequest := app.Group("/access-requests", middleware.Jwt, middleware.AdminMiddleware)
+
+	accessRequest.Get("/", handler.getPagination)
+	accessRequest.Post("/:id/approve", handler.approve)
+	accessRequest.Post("/:id/deny", handler.deny)
+}
+
+func (h *accessRequestHandler) getPagination(c *fiber.Ctx) error {
+	r := requests.AccessRequestQuery{
+		PageIndex: "0",
+		PageSize:  "20",
+	}
+
+	err := c.QueryParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	accessRequests, err := h.service.GetPagination(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	count, err := h.service.Count(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"accessRequests": accessRequests,
+		"totalRows":      count,
+	})
+}
+
+func reviewError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, nerrors.ErrCannotParseUUID), errors.Is(err, nerrors.ErrAccessRequestNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "ACCESS_REQUEST_NOT_FOUND",
+			"message": "Access request not found",
+		})
+	case errors.Is(err, nerrors.ErrAccessRequestAlreadyReviewed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":    "ACCESS_REQUEST_ALREADY_REVIEWED",
+			"message": "Access request is already reviewed",
+		})
+	case errors.Is(err, nerrors.ErrEventNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "EVENT_NOT_FOUND",
+			"message": "Event not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+}
+
+func (h *accessRequestHandler) approve(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var r requests.ApproveAccessRequest
+	if err := c.BodyParser(&r); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse request body",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	accessRequest, err := h.service.Approve(id, auditActor(c), &r)
+	if err != nil {
+		return reviewError(c, err)
+	}
+
+	return c.JSON(accessRequest)
+}
+
+func (h *accessRequestHandler) deny(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	accessRequest, err := h.service.Deny(id, auditActor(c))
+	if err != nil {
+		return reviewError(c, err)
+	}
+
+	return c.JSON(accessRequest)
+}
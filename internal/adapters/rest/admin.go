@@ -46,7 +46,7 @@ func (h *adminHandler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs)
 	}
 
-	if err := h.service.Create(&r); err != nil {
+	if err := h.service.Create(auditActor(c), &r); err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrAdminAlreadyExists):
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -88,7 +88,7 @@ func (h *adminHandler) UpdateById(c *fiber.Ctx) error {
 		Email:    r.Email,
 	}
 
-	err := h.service.UpdateById(id, payload)
+	err := h.service.UpdateById(id, auditActor(c), payload)
 	if err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrCannotParseUUID):
@@ -162,7 +162,7 @@ func (h *adminHandler) DeleteByIds(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs)
 	}
 
-	err = h.service.DeleteByIds(ids.Id)
+	err = h.service.DeleteByIds(ids.Id, auditActor(c))
 	if err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrAdminNotFound):
@@ -0,0 +1,180 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// bearerApiKey returns the API key sent as "Authorization: Bearer nsk_...".
+func bearerApiKey(c *fiber.Ctx) (string, bool) {
+	token, ok := middleware.BearerToken(c)
+	if !ok || !libs.IsApiKey(token) {
+		return "", false
+	}
+
+	return token, true
+}
+
+// addParticipantWithApiKey lets kiosks scan with an API key. Requests without
+// one fall through to the signed-in route.
+func (h *eventHandler) addParticipantWithApiKey(c *fiber.Ctx) error {
+	key, ok := bearerApiKey(c)
+	if !ok {
+		return c.Next()
+	}
+
+	apiKey, err := h.apiKeyService.Authenticate(key, c.Params("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrApiKeyNotFound):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "INVALID_API_KEY",
+				"message": "Invalid api key",
+			})
+		case errors.Is(err, nerrors.ErrApiKeyRevoked):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "API_KEY_REVOKED",
+				"message": "Api key has been revoked",
+			})
+		case errors.Is(err, nerrors.ErrApiKeyExpired):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "API_KEY_EXPIRED",
+				"message": "Api key has expired",
+			})
+		case errors.Is(err, nerrors.ErrApiKeyNotAllowed):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":    "FORBIDDEN",
+				"message": "Api key is not allowed for this event",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	c.Locals("apiKey", *apiKey)
+
+	err = h.eventService.CheckParticipantChanges(c.Params("id"), false)
+	if err != nil {
+		return eventStatusError(c, err)
+	}
+
+	return h.addParticipant(c)
+}
+
+func (h *eventHandler) getApiKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyService.GetByEventId(c.Params("id"))
+	if err != nil {
+		if errors.Is(err, nerrors.ErrCannotParseUUID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(keys)
+}
+
+func (h *eventHandler) createApiKey(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	var r requests.ApiKeyRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse request body",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	apiKey, key, err := h.apiKeyService.Create(eventId, auditActor(c), &r)
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrCannotParseUUID):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		case errors.Is(err, nerrors.ErrInvalidApiKeyExpiry):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_API_KEY_EXPIRY",
+				"message": "Api key expiry must be in the future",
+			})
+		case errors.Is(err, nerrors.ErrEventNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":   "SUCCESS",
+		"apiKey": apiKey,
+		"key":    key,
+	})
+}
+
+func (h *eventHandler) revokeApiKey(c *fiber.Ctx) error {
+	keyId := c.Params("keyId")
+
+	err := h.apiKeyService.Revoke(c.Params("id"), keyId, auditActor(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrCannotParseUUID):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		case errors.Is(err, nerrors.ErrApiKeyNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "API_KEY_NOT_FOUND",
+				"message": "Api key not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "Revoke Api Key Success",
+	})
+}
+
+// apiKeyActor returns the API key a kiosk request was authenticated with.
+func apiKeyActor(c *fiber.Ctx) (*entities.ApiKey, bool) {
+	apiKey, ok := c.Locals("apiKey").(entities.ApiKey)
+	if !ok {
+		return nil, false
+	}
+
+	return &apiKey, true
+}
@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type auditHandler struct {
+	app     *fiber.App
+	service services.AuditService
+}
+
+func NewAuditHandler(app *fiber.App, service services.AuditService) {
+	handler := &auditHandler{
+		app:     app,
+		service: service,
+	}
+
+	audit := app.Group("/audit", middleware.Jwt, middleware.AdminMiddleware)
+
+	audit.Get("/", handler.getPagination)
+}
+
+// auditActor returns the signed in user making the request, services record
+// their changes against it in the audit log.
+func auditActor(c *fiber.Ctx) *entities.Actor {
+	claims, _ := c.Locals("token").(middleware.AccessToken)
+
+	return &entities.Actor{
+		Email: claims.Email,
+		Role:  claims.Role,
+		Ip:    c.IP(),
+	}
+}
+
+func (h *auditHandler) getPagination(c *fiber.Ctx) error {
+	r := requests.AuditLogQuery{
+		PageIndex: "0",
+		PageSize:  "20",
+	}
+
+	err := c.QueryParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	logs, err := h.service.GetPagination(&r)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrInvalidDateRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_DATE_RANGE",
+				"message": "Dates must be YYYY-MM-DD and from must not be after to",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	count, err := h.service.Count(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"logs":      logs,
+		"totalRows": count,
+	})
+}
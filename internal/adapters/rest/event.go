@@ -14,63 +14,96 @@ import (
 )
 
 type eventHandler struct {
-	app                *fiber.App
-	adminService       services.AdminService
-	eventService       services.EventService
-	staffService       services.StaffService
-	participantService services.ParticipantService
+	app                 *fiber.App
+	adminService        services.AdminService
+	eventService        services.EventService
+	staffService        services.StaffService
+	participantService  services.ParticipantService
+	sessionService      services.SessionService
+	feedService         services.FeedService
+	apiKeyService       services.ApiKeyService
+	registrationService services.RegistrationService
 }
 
-func NewEventHandler(app *fiber.App, adminService services.AdminService, eventService services.EventService, staffService services.StaffService, participantService services.ParticipantService) {
+func NewEventHandler(app *fiber.App, adminService services.AdminService, eventService services.EventService, staffService services.StaffService, participantService services.ParticipantService, sessionService services.SessionService, feedService services.FeedService, apiKeyService services.ApiKeyService, registrationService services.RegistrationService) {
 	handler := eventHandler{
-		app:                app,
-		adminService:       adminService,
-		eventService:       eventService,
-		staffService:       staffService,
-		participantService: participantService,
+		app:                 app,
+		adminService:        adminService,
+		eventService:        eventService,
+		staffService:        staffService,
+		participantService:  participantService,
+		sessionService:      sessionService,
+		feedService:         feedService,
+		apiKeyService:       apiKeyService,
+		registrationService: registrationService,
 	}
 
-	event := app.Group("/events", middleware.Jwt, func(c *fiber.Ctx) error {
+	// Kiosks scan with an API key instead of signing in, so this route is
+	// matched before the group requires an access token.
+	app.Post("/events/:id/participants", handler.addParticipantWithApiKey)
 
-		claims, ok := c.Locals("token").(middleware.AccessToken)
-		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"code":    "UNAUTHORIZED",
-				"message": "Unauthorized",
-			})
-		}
-
-		isAddParticipantPath := fiber.RoutePatternMatch(c.Path(), "/events/:id/participants")
-		isGetEventPath := fiber.RoutePatternMatch(c.Path(), "/events/:id")
-
-		if !isAddParticipantPath && !isGetEventPath {
-			if claims.Role != "admin" {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"code":    "UNAUTHORIZED",
-					"message": "Unauthorized",
-				})
-			}
-		}
-
-		return c.Next()
-	})
+	event := app.Group("/events", middleware.Jwt)
 
 	staffMiddeleware := middleware.NewStaffMiddleware(staffService)
-
-	event.Get("/", handler.getPagination)
-	event.Get("/:id", staffMiddeleware.Staff, handler.getById)
-	event.Post("/", handler.create)
-	event.Put("/:id", handler.updateById)
-	event.Delete("/:id", handler.deleteById)
+	scanner := staffMiddeleware.Role(entities.StaffScanner)
+	supervisor := staffMiddeleware.Role(entities.StaffSupervisor)
+	coOrganiser := staffMiddeleware.Role(entities.StaffCoOrganiser)
+
+	event.Get("/", middleware.AdminMiddleware, handler.getPagination)
+	event.Get("/:id", scanner, handler.getById)
+	event.Post("/", middleware.AdminMiddleware, handler.create)
+	event.Put("/:id", coOrganiser, handler.updateById)
+	event.Delete("/:id", middleware.AdminMiddleware, handler.deleteById)
+
+	// Lifecycle
+	event.Post("/:id/publish", coOrganiser, handler.transition(entities.EventPublished))
+	event.Post("/:id/open", coOrganiser, handler.transition(entities.EventOpen))
+	event.Post("/:id/close", coOrganiser, handler.transition(entities.EventClosed))
+	event.Post("/:id/archive", middleware.AdminMiddleware, handler.transition(entities.EventArchived))
+
+	// Capacity
+	event.Get("/:id/capacity", scanner, handler.getCapacity)
+	event.Put("/:id/capacity", middleware.AdminMiddleware, handler.setCapacity)
+
+	// Registrations
+	event.Put("/:id/registration-mode", middleware.AdminMiddleware, handler.setRegistrationMode)
+	registrations := event.Group("/:id/registrations")
+	registrations.Get("/", supervisor, handler.getRegistrations)
+	registrations.Get("/summary", supervisor, handler.getRegistrationSummary)
+	registrations.Post("/", middleware.AdminMiddleware, handler.createRegistrations)
+	registrations.Post("/import", middleware.AdminMiddleware, handler.importRegistrations)
+	registrations.Delete("/:studentId", middleware.AdminMiddleware, handler.deleteRegistration)
 
 	// Staffs
-	event.Post("/:id/staffs/set", handler.setStaffs)
+	event.Post("/:id/staffs/set", coOrganiser, handler.setStaffs)
 
 	// Participants
-	participants := event.Group("/:id/participants", staffMiddeleware.Staff)
-	participants.Get("/", handler.getParticipantsPagination)
-	participants.Post("/", handler.addParticipant)
-	participants.Post("/batchdelete", handler.removeParticipant)
+	participants := event.Group("/:id/participants")
+	participants.Get("/", supervisor, handler.getParticipantsPagination)
+	participants.Get("/export", supervisor, handler.exportParticipants)
+	participants.Post("/", scanner, handler.participantChanges, handler.addParticipant)
+	participants.Post("/checkout", scanner, handler.participantChanges, handler.checkOutParticipant)
+	participants.Post("/sync", scanner, handler.participantChanges, handler.syncParticipants)
+	participants.Post("/batchdelete", supervisor, handler.participantChanges, handler.removeParticipant)
+	participants.Get("/stats/staffs", supervisor, handler.getStaffScanCounts)
+	participants.Get("/stats/timeline", supervisor, handler.getStaffScanTimeline)
+
+	// Live feed
+	event.Get("/:id/feed", scanner, handler.getFeed)
+
+	// Sessions
+	sessions := event.Group("/:id/sessions")
+	sessions.Get("/", scanner, handler.getSessions)
+	sessions.Get("/attendance", supervisor, handler.getSessionAttendance)
+	sessions.Post("/", coOrganiser, handler.createSession)
+	sessions.Put("/:sessionId", coOrganiser, handler.updateSession)
+	sessions.Delete("/:sessionId", coOrganiser, handler.deleteSession)
+
+	// API keys
+	apiKeys := event.Group("/:id/api-keys", middleware.AdminMiddleware)
+	apiKeys.Get("/", handler.getApiKeys)
+	apiKeys.Post("/", handler.createApiKey)
+	apiKeys.Delete("/:keyId", handler.revokeApiKey)
 
 }
 
@@ -105,7 +138,7 @@ func (h *eventHandler) create(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.eventService.Create(&r, admin.Id.String())
+	err = h.eventService.Create(&r, admin.Id.String(), auditActor(c))
 	if err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrEventAlreadyExists):
@@ -113,6 +146,11 @@ func (h *eventHandler) create(c *fiber.Ctx) error {
 				"code":    "EVENT_ALREADY_EXISTS",
 				"message": "This event is already exists",
 			})
+		case errors.Is(err, nerrors.ErrInvalidScanWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_SCAN_WINDOW",
+				"message": "Scan window end is before start",
+			})
 		case errors.Is(err, nerrors.ErrAdminNotFound):
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":    "ADMIN_ID_ERROR",
@@ -136,11 +174,19 @@ func (h *eventHandler) create(c *fiber.Ctx) error {
 
 func (h *eventHandler) getPagination(c *fiber.Ctx) error {
 	search := c.Query("search")
+	status := c.Query("status")
 	pageIndex := c.Query("pageIndex")
 	pageSize := c.Query("pageSize")
 
-	events, err := h.eventService.GetPagination(search, pageIndex, pageSize)
+	events, err := h.eventService.GetPagination(search, status, pageIndex, pageSize)
 	if err != nil {
+		if errors.Is(err, nerrors.ErrInvalidEventStatus) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_EVENT_STATUS",
+				"message": "Status must be draft, published, open, closed or archived",
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":    "SOMETHING_WENT_WRONG",
 			"message": "Some thing went wrong",
@@ -158,13 +204,20 @@ func (h *eventHandler) getPagination(c *fiber.Ctx) error {
 			})
 		}
 		responseEvents = append(responseEvents, &responses.EventResponse{
-			ID:                event.Id,
-			Name:              event.Name,
-			Place:             event.Place,
-			Date:              event.Date,
-			Host:              event.Host,
-			Owner:             event.Owner,
-			ParticipantsCount: *count,
+			ID:                 event.Id,
+			Name:               event.Name,
+			Place:              event.Place,
+			Date:               event.Date,
+			Host:               event.Host,
+			Owner:              event.Owner,
+			StartAt:            event.StartAt,
+			EndAt:              event.EndAt,
+			GraceBeforeMinutes: event.GraceBeforeMinutes,
+			GraceAfterMinutes:  event.GraceAfterMinutes,
+			Status:             event.Status,
+			Capacity:           event.Capacity,
+			RegistrationMode:   event.RegistrationMode,
+			ParticipantsCount:  *count,
 		})
 	}
 
@@ -172,7 +225,7 @@ func (h *eventHandler) getPagination(c *fiber.Ctx) error {
 		responseEvents = []*responses.EventResponse{}
 	}
 
-	count, err := h.eventService.GetEventsCount(search)
+	count, err := h.eventService.GetEventsCount(search, status)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":    "SOMETHING_WENT_WRONG",
@@ -217,14 +270,40 @@ func (h *eventHandler) getById(c *fiber.Ctx) error {
 		staffs = []*entities.Staff{}
 	}
 
+	sessions, err := h.sessionService.GetAllFromEventId(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	capacity, err := h.eventService.GetCapacity(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
 	return c.JSON(fiber.Map{
-		"id":     event.Id,
-		"name":   event.Name,
-		"place":  event.Place,
-		"date":   event.Date,
-		"host":   event.Host,
-		"owner":  event.Owner,
-		"staffs": staffs,
+		"id":                 event.Id,
+		"name":               event.Name,
+		"place":              event.Place,
+		"date":               event.Date,
+		"host":               event.Host,
+		"owner":              event.Owner,
+		"startAt":            event.StartAt,
+		"endAt":              event.EndAt,
+		"graceBeforeMinutes": event.GraceBeforeMinutes,
+		"graceAfterMinutes":  event.GraceAfterMinutes,
+		"status":             event.Status,
+		"capacity":           capacity.Capacity,
+		"admitted":           capacity.Admitted,
+		"remaining":          capacity.Remaining,
+		"registrationMode":   event.RegistrationMode,
+		"staffs":             staffs,
+		"sessions":           sessions,
 	})
 }
 
@@ -246,7 +325,7 @@ func (h *eventHandler) deleteById(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.eventService.DeleteById(id)
+	err = h.eventService.DeleteById(id, auditActor(c))
 	if err != nil {
 		if errors.Is(err, nerrors.ErrEventNotFound) {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -260,6 +339,7 @@ func (h *eventHandler) deleteById(c *fiber.Ctx) error {
 			"message": "Something went wrong",
 		})
 	}
+
 	return c.JSON(fiber.Map{
 		"code":    "SUCCESS",
 		"message": "Delete Event Success",
@@ -293,7 +373,7 @@ func (h *eventHandler) updateById(c *fiber.Ctx) error {
 		}
 	}
 
-	err = h.eventService.UpdateById(id, r)
+	err = h.eventService.UpdateById(id, auditActor(c), r)
 	if err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrEventAlreadyExists):
@@ -301,6 +381,16 @@ func (h *eventHandler) updateById(c *fiber.Ctx) error {
 				"code":    "EVENT_ALREADY_EXISTS",
 				"message": "This event is already exists",
 			})
+		case errors.Is(err, nerrors.ErrInvalidScanWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_SCAN_WINDOW",
+				"message": "Scan window end is before start",
+			})
+		case errors.Is(err, nerrors.ErrEventLocked):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":    "EVENT_LOCKED",
+				"message": "Archived events cannot be edited",
+			})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":    "SOMETHING_WENT_WRONG",
@@ -331,7 +421,7 @@ func (h *eventHandler) setStaffs(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs)
 	}
 
-	err = h.staffService.SetStaffs(r.Email, eventId)
+	err = h.staffService.SetStaffs(eventId, auditActor(c), &r)
 	if err != nil {
 		switch {
 		case errors.Is(err, nerrors.ErrEventNotFound):
@@ -345,6 +435,12 @@ func (h *eventHandler) setStaffs(c *fiber.Ctx) error {
 				"code":    "STAFF_ALREADY_EXISTS",
 				"message": "Staff already exists",
 			})
+
+		case errors.Is(err, nerrors.ErrInvalidStaffRole):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_STAFF_ROLE",
+				"message": "Invalid staff role",
+			})
 		}
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -435,13 +531,47 @@ func (h *eventHandler) addParticipant(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs)
 	}
 
-	participant, err := h.participantService.AddParticipant(eventId, &r)
+	actor, ok := scanActor(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_DEVICE_ID",
+			"message": "Device id must be at most 100 characters",
+		})
+	}
+
+	participant, err := h.participantService.AddParticipant(eventId, actor, &r)
 	if err != nil {
-		if errors.Is(err, nerrors.ErrParticipantAlreadyExists) {
+		switch {
+		case errors.Is(err, nerrors.ErrParticipantAlreadyExists):
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":    "PARTICIPANT_ALREADY_EXISTS",
 				"message": "Participant already exists",
 			})
+		case errors.Is(err, nerrors.ErrEventFull):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":    "EVENT_FULL",
+				"message": "Event has reached its capacity",
+			})
+		case errors.Is(err, nerrors.ErrNotRegistered):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":    "NOT_REGISTERED",
+				"message": "Student is not registered for this event",
+			})
+		case errors.Is(err, nerrors.ErrScanOutsideWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "SCAN_OUTSIDE_WINDOW",
+				"message": "Scan is outside of event scan window",
+			})
+		case errors.Is(err, nerrors.ErrSessionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "SESSION_NOT_FOUND",
+				"message": "Session not found",
+			})
+		case errors.Is(err, nerrors.ErrInvalidBarcode):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_BARCODE",
+				"message": "Invalid student barcode",
+			})
 		}
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -450,12 +580,136 @@ func (h *eventHandler) addParticipant(c *fiber.Ctx) error {
 		})
 	}
 
+	// Walk-ins are still admitted, the warning lets the scanner notice them
+	if participant.Registered != nil && !*participant.Registered {
+		return c.JSON(fiber.Map{
+			"code":        "SUCCESS",
+			"warning":     "NOT_REGISTERED",
+			"participant": participant,
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"code":        "SUCCESS",
 		"participant": participant,
 	})
 }
 
+func (h *eventHandler) checkOutParticipant(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	_, err := h.eventService.GetById(eventId)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrEventNotFound) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	var r requests.AddParticipant
+	err = c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	participant, err := h.participantService.CheckOutParticipant(eventId, auditActor(c), &r)
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrParticipantNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "PARTICIPANT_NOT_FOUND",
+				"message": "Participant has not checked in",
+			})
+		case errors.Is(err, nerrors.ErrParticipantAlreadyCheckedOut):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":    "PARTICIPANT_ALREADY_CHECKED_OUT",
+				"message": "Participant already checked out",
+			})
+		case errors.Is(err, nerrors.ErrCheckOutBeforeCheckIn):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "CHECK_OUT_BEFORE_CHECK_IN",
+				"message": "Check out time is before check in time",
+			})
+		case errors.Is(err, nerrors.ErrScanOutsideWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "SCAN_OUTSIDE_WINDOW",
+				"message": "Scan is outside of event scan window",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":        "SUCCESS",
+		"participant": participant,
+	})
+}
+
+func (h *eventHandler) syncParticipants(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	var r requests.SyncParticipants
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	actor, ok := scanActor(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_DEVICE_ID",
+			"message": "Device id must be at most 100 characters",
+		})
+	}
+
+	results, err := h.participantService.SyncParticipants(eventId, actor, &r)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrEventNotFound) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"results": results,
+	})
+}
+
 func (h *eventHandler) removeParticipant(c *fiber.Ctx) error {
 	eventId := c.Params("id")
 
@@ -490,7 +744,7 @@ func (h *eventHandler) removeParticipant(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errs)
 	}
 
-	err = h.participantService.RemoveParticipants(eventId, r.Barcodes)
+	err = h.participantService.RemoveParticipants(eventId, auditActor(c), r.Barcodes)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":    "SOMETHING_WENT_WRONG",
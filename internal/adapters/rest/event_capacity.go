@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/gofiber/fiber/v2"
+)
+
+func eventCapacityError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, nerrors.ErrCannotParseUUID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse uuid",
+		})
+	case errors.Is(err, nerrors.ErrEventNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "EVENT_NOT_FOUND",
+			"message": "Event not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+}
+
+func (h *eventHandler) getCapacity(c *fiber.Ctx) error {
+	capacity, err := h.eventService.GetCapacity(c.Params("id"))
+	if err != nil {
+		return eventCapacityError(c, err)
+	}
+
+	return c.JSON(capacity)
+}
+
+func (h *eventHandler) setCapacity(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var r requests.EventCapacityRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	capacity, err := h.eventService.SetCapacity(id, auditActor(c), &r)
+	if err != nil {
+		return eventCapacityError(c, err)
+	}
+
+	return c.JSON(capacity)
+}
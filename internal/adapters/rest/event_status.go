@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func eventStatusError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, nerrors.ErrCannotParseUUID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse uuid",
+		})
+	case errors.Is(err, nerrors.ErrEventNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "EVENT_NOT_FOUND",
+			"message": "Event not found",
+		})
+	case errors.Is(err, nerrors.ErrEventTransition):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":    "INVALID_EVENT_TRANSITION",
+			"message": "Event cannot move to this status",
+		})
+	case errors.Is(err, nerrors.ErrEventNotOpen):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":    "EVENT_NOT_OPEN",
+			"message": "Event is not open for scanning",
+		})
+	case errors.Is(err, nerrors.ErrEventLocked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":    "EVENT_LOCKED",
+			"message": "Event is closed or archived",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+}
+
+// participantChanges guards the routes that change participants. Admins can
+// still change an event that is not open by passing override=true.
+func (h *eventHandler) participantChanges(c *fiber.Ctx) error {
+	claims, _ := c.Locals("token").(middleware.AccessToken)
+	override := claims.Role == "admin" && c.QueryBool("override")
+
+	err := h.eventService.CheckParticipantChanges(c.Params("id"), override)
+	if err != nil {
+		return eventStatusError(c, err)
+	}
+
+	return c.Next()
+}
+
+// transition returns a handler moving the event to status.
+func (h *eventHandler) transition(status entities.EventStatus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		event, err := h.eventService.UpdateStatus(id, auditActor(c), status)
+		if err != nil {
+			return eventStatusError(c, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"code":   "SUCCESS",
+			"status": event.Status,
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/gofiber/fiber/v2"
+)
+
+var exportHeader = []string{
+	"Barcode",
+	"Timestamp",
+	"Checked Out At",
+	"Dwell Seconds",
+	"Scanned By",
+	"Device ID",
+	"Student ID",
+	"Full Name",
+	"Faculty",
+	"Major",
+}
+
+func exportRow(participant entities.Participant) []string {
+	row := []string{
+		participant.Barcode,
+		participant.Timestamp.Format(time.RFC3339),
+		"",
+		"",
+		participant.ScannedBy,
+		participant.DeviceId,
+		participant.StudentId,
+		"",
+		participant.Faculty,
+		"",
+	}
+
+	if participant.CheckedOutAt != nil {
+		row[2] = participant.CheckedOutAt.Format(time.RFC3339)
+	}
+
+	if participant.DwellSeconds != nil {
+		row[3] = strconv.FormatInt(*participant.DwellSeconds, 10)
+	}
+
+	if participant.Student != nil {
+		row[7] = participant.Student.FullName
+		row[8] = participant.Student.Faculty
+		row[9] = participant.Student.Major
+	}
+
+	return row
+}
+
+func (h *eventHandler) exportParticipants(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+	format := c.Query("format", "csv")
+
+	if format != "csv" && format != "xlsx" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "UNSUPPORTED_FILE_FORMAT",
+			"message": "Only CSV and XLSX files are supported",
+		})
+	}
+
+	_, err := h.eventService.GetById(eventId)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrEventNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	c.Attachment(fmt.Sprintf("%s-participants.%s", eventId, format))
+
+	// The status and headers are already sent once streaming starts, so
+	// failures from here on can only be logged and end the download early.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		sheet, err := libs.NewSpreadsheetWriter(format, w)
+		if err != nil {
+			log.Println("Cannot export participants: ", err)
+			return
+		}
+
+		err = sheet.WriteRow(exportHeader)
+		if err != nil {
+			log.Println("Cannot export participants: ", err)
+			return
+		}
+
+		err = h.participantService.ExportParticipants(eventId, func(participants []entities.Participant) error {
+			for _, participant := range participants {
+				err := sheet.WriteRow(exportRow(participant))
+				if err != nil {
+					return err
+				}
+			}
+
+			err := sheet.Flush()
+			if err != nil {
+				return err
+			}
+
+			return w.Flush()
+		})
+		if err != nil {
+			log.Println("Cannot export participants: ", err)
+			return
+		}
+
+		err = sheet.Close()
+		if err != nil {
+			log.Println("Cannot export participants: ", err)
+		}
+	})
+
+	return nil
+}
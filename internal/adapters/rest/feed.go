@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/gofiber/fiber/v2"
+)
+
+const feedKeepAliveInterval = 15 * time.Second
+
+func writeFeedEvent(w *bufio.Writer, event entities.FeedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// getFeed streams participant changes of the event as Server-Sent Events,
+// starting with the current headcount.
+func (h *eventHandler) getFeed(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	_, err := h.eventService.GetById(eventId)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrEventNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	count, err := h.participantService.GetCountParticipants(eventId, "")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	events, unsubscribe, err := h.feedService.Subscribe(eventId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		err := writeFeedEvent(w, entities.FeedEvent{
+			Type:      entities.FeedHeadcount,
+			EventId:   eventId,
+			Headcount: *count,
+		})
+		if err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(feedKeepAliveInterval)
+		defer ticker.Stop()
+
+		// A failed write means the client went away.
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				if writeFeedEvent(w, event) != nil {
+					return
+				}
+			case <-ticker.C:
+				_, err := w.WriteString(": keep-alive\n\n")
+				if err != nil || w.Flush() != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/gofiber/fiber/v2"
+)
+
+type jwksHandler struct {
+	app     *fiber.App
+	service services.SigningKeyService
+}
+
+func NewJwksHandler(app *fiber.App, service services.SigningKeyService) {
+	handler := &jwksHandler{
+		app:     app,
+		service: service,
+	}
+
+	app.Get("/.well-known/jwks.json", handler.getJwks)
+}
+
+func (h *jwksHandler) getJwks(c *fiber.Ctx) error {
+	// Verifiers may cache the set for as long as a new key is published
+	// before it signs
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(libs.KeyPublishAhead.Seconds())))
+
+	return c.JSON(h.service.JWKS())
+}
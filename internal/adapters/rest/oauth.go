@@ -2,89 +2,186 @@ package rest
 
 import (
 	"errors"
+	"log"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/configs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 )
 
-type GoogleAuthHandler struct {
-	c            *oauth2.Config
-	states       map[string]bool
-	webUrl       string
-	oAuthService services.OAuthService
-	tokenService services.TokenService
-	store        *session.Store
-	signInUrl    string
-	signInError  string
+type AuthHandler struct {
+	c                  *oauth2.Config
+	webUrl             string
+	oAuthService       services.OAuthService
+	tokenService       services.TokenService
+	store              *session.Store
+	signInUrl          string
+	signInError        string
+	nativeRedirectUris []string
 }
 
-func NewAuthHandler(app *fiber.App, oAuthService services.OAuthService, tokenService services.TokenService) {
+// parseRedirectUris reads a comma separated list such as
+// "nisitscan://auth/callback".
+func parseRedirectUris(value string) []string {
+	var uris []string
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
+
+// NewAuthHandler keeps the session that binds a login to its browser in
+// storage, which must be shared by every instance behind the load balancer.
+func NewAuthHandler(app *fiber.App, oAuthService services.OAuthService, tokenService services.TokenService, storage fiber.Storage) {
 
-	store := session.New()
+	store := session.New(session.Config{
+		Storage:        storage,
+		CookieHTTPOnly: true,
+		CookieSecure:   true,
+		CookieSameSite: "Lax",
+	})
 
-	handler := &GoogleAuthHandler{
-		states:       make(map[string]bool),
-		webUrl:       os.Getenv("WEB_URL"),
-		oAuthService: oAuthService,
-		tokenService: tokenService,
-		store:        store,
-		signInUrl:    os.Getenv("WEB_URL") + "/auth/sign-in",
-		signInError:  os.Getenv("WEB_URL") + "/auth/sign-in?error=something-went-wrong",
+	handler := &AuthHandler{
+		webUrl:             os.Getenv("WEB_URL"),
+		oAuthService:       oAuthService,
+		tokenService:       tokenService,
+		store:              store,
+		signInUrl:          os.Getenv("WEB_URL") + "/auth/sign-in",
+		signInError:        os.Getenv("WEB_URL") + "/auth/sign-in?error=something-went-wrong",
+		nativeRedirectUris: parseRedirectUris(os.Getenv("NATIVE_REDIRECT_URIS")),
 	}
 
 	auth := app.Group("/auth")
 
-	auth.Get("/google", handler.auth)
-	auth.Get("/google/callback", handler.callback)
+	auth.Get("/providers", handler.providers)
 	auth.Post("/logout", handler.logout)
 	auth.Post("/refresh", handler.refreshToken)
+	auth.Post("/token", handler.exchangeToken)
+	auth.Post("/token/refresh", handler.refreshTokenJson)
+	auth.Get("/sessions", middleware.Jwt, handler.getSessions)
+	auth.Delete("/sessions", middleware.Jwt, handler.revokeAllSessions)
+	auth.Delete("/sessions/:id", middleware.Jwt, handler.revokeSession)
+	auth.Delete("/users/:email/sessions", middleware.Jwt, middleware.AdminMiddleware, handler.forceLogout)
+	auth.Get("/:provider", handler.auth)
+	auth.Get("/:provider/callback", handler.callback)
 }
 
-func (h *GoogleAuthHandler) auth(c *fiber.Ctx) error {
-	url, err := h.oAuthService.Auth()
+func (h *AuthHandler) providers(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"providers": h.oAuthService.Providers(),
+	})
+}
+
+func (h *AuthHandler) auth(c *fiber.Ctx) error {
+	sess, err := h.store.Get(c)
 	if err != nil {
 		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
 	}
 
-	sess, err := h.store.Get(c)
+	url, err := h.oAuthService.Auth(c.Params("provider"), sess.ID())
 	if err != nil {
+		if errors.Is(err, nerrors.ErrOAuthProviderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "PROVIDER_NOT_FOUND",
+				"message": "Provider not found",
+			})
+		}
+
+		log.Println("Cannot start oauth login: ", err)
 		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
 	}
 
 	redirectTo := c.Query("redirect_to")
 	if redirectTo != "" {
 		sess.Set("redirect_to", redirectTo)
-		if err := sess.Save(); err != nil {
-			return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
+	}
+
+	// Native apps pass their own redirect URI and a PKCE challenge, the login
+	// then ends with a code for POST /auth/token instead of cookies.
+	nativeRedirect := c.Query("redirect_uri")
+	if nativeRedirect != "" {
+		codeChallenge := c.Query("code_challenge")
+		if !slices.Contains(h.nativeRedirectUris, nativeRedirect) || codeChallenge == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REDIRECT_URI",
+				"message": "Redirect uri is not allowed",
+			})
 		}
+
+		sess.Set("native_redirect", nativeRedirect)
+		sess.Set("code_challenge", codeChallenge)
+	} else {
+		sess.Delete("native_redirect")
+		sess.Delete("code_challenge")
+	}
+
+	// Always save so the session cookie binding the state reaches the browser
+	if err := sess.Save(); err != nil {
+		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
 	}
 
 	return c.Redirect(*url, fiber.StatusTemporaryRedirect)
 }
 
-func (h *GoogleAuthHandler) callback(c *fiber.Ctx) error {
+func (h *AuthHandler) callback(c *fiber.Ctx) error {
+	sess, err := h.store.Get(c)
+	if err != nil {
+		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
+	}
+
 	code := c.Query("code")
 	state := c.Query("state")
-	email, token, err := h.oAuthService.Callback(code, state)
+	login, err := h.oAuthService.Callback(c.Params("provider"), code, state, sess.ID())
 	if err != nil {
-		return c.Redirect(h.signInUrl+"?error=unauthorized", fiber.StatusTemporaryRedirect)
+		if errors.Is(err, nerrors.ErrOAuthProviderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "PROVIDER_NOT_FOUND",
+				"message": "Provider not found",
+			})
+		}
+
+		if errors.Is(err, nerrors.ErrAccessRequestPending) {
+			return c.Redirect(h.signInUrl+"?error=access-pending", fiber.StatusTemporaryRedirect)
+		}
+
+		if errors.Is(err, nerrors.ErrEmailDomainNotAllowed) {
+			return c.Redirect(h.signInUrl+"?error=domain-not-allowed", fiber.StatusTemporaryRedirect)
+		}
+
+		if errors.Is(err, nerrors.ErrEmailNotVerified) {
+			return c.Redirect(h.signInUrl+"?error=email-not-verified", fiber.StatusTemporaryRedirect)
+		}
+
+		if errors.Is(err, nerrors.ErrUserNotFound) || errors.Is(err, nerrors.ErrInvalidOAuthState) || errors.Is(err, nerrors.ErrInvalidOAuthNonce) {
+			return c.Redirect(h.signInUrl+"?error=unauthorized", fiber.StatusTemporaryRedirect)
+		}
+
+		log.Println("Cannot complete oauth login: ", err)
+		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
 	}
 
-	err = h.tokenService.RemoveToken(*email)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"code":    "SOMETHING_WENT_WRONG",
-			"message": "Something went wrong",
-		})
+	if nativeRedirect, ok := sess.Get("native_redirect").(string); ok {
+		return h.nativeCallback(c, sess, nativeRedirect, login)
 	}
 
-	err = h.tokenService.AddRefreshToken(*email, token.RefreshToken)
+	token, err := h.startSession(c, login)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":    "SOMETHING_WENT_WRONG",
@@ -114,11 +211,6 @@ func (h *GoogleAuthHandler) callback(c *fiber.Ctx) error {
 
 	c.Cookie(refreshToken)
 
-	sess, err := h.store.Get(c)
-	if err != nil {
-		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
-	}
-
 	redirectTo := sess.Get("redirect_to")
 
 	if redirectTo == nil {
@@ -130,7 +222,168 @@ func (h *GoogleAuthHandler) callback(c *fiber.Ctx) error {
 	return c.Redirect(h.webUrl+redirectTo.(string), fiber.StatusTemporaryRedirect)
 }
 
-func (h *GoogleAuthHandler) logout(c *fiber.Ctx) error {
+// startSession issues the tokens of login and records its refresh token for
+// the device making the request.
+func (h *AuthHandler) startSession(c *fiber.Ctx, login *services.OAuthLogin) (*services.AuthToken, error) {
+	sessionId, err := uuid.Parse(login.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.tokenService.IssueTokens(login.SessionId, login.Email, login.Name, login.Picture, login.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.tokenService.AddRefreshToken(&entities.RefreshToken{
+		Id:        sessionId,
+		Email:     login.Email,
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Ip:        c.IP(),
+	}, token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// nativeCallback sends the app back to its redirect URI with a one-time code
+// for POST /auth/token. No tokens exist until the code is redeemed there.
+func (h *AuthHandler) nativeCallback(c *fiber.Ctx, sess *session.Session, nativeRedirect string, login *services.OAuthLogin) error {
+	codeChallenge, _ := sess.Get("code_challenge").(string)
+
+	sess.Delete("native_redirect")
+	sess.Delete("code_challenge")
+	if err := sess.Save(); err != nil {
+		log.Println("Cannot save session: ", err)
+	}
+
+	code, err := h.oAuthService.CreateExchangeCode(login, codeChallenge)
+	if err != nil {
+		log.Println("Cannot create exchange code: ", err)
+		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
+	}
+
+	redirectUrl, err := url.Parse(nativeRedirect)
+	if err != nil {
+		return c.Redirect(h.signInError, fiber.StatusTemporaryRedirect)
+	}
+
+	query := redirectUrl.Query()
+	query.Set("code", code)
+	redirectUrl.RawQuery = query.Encode()
+
+	return c.Redirect(redirectUrl.String(), fiber.StatusTemporaryRedirect)
+}
+
+func tokenJson(token *services.AuthToken) fiber.Map {
+	return fiber.Map{
+		"code":                "SUCCESS",
+		"tokenType":           "Bearer",
+		"accessToken":         token.AccessToken,
+		"accessTokenExpired":  token.AccessTokenExpired,
+		"refreshToken":        token.RefreshToken,
+		"refreshTokenExpired": token.RefreshTokenExpired,
+	}
+}
+
+func (h *AuthHandler) exchangeToken(c *fiber.Ctx) error {
+	var r requests.TokenExchangeRequest
+	if err := c.BodyParser(&r); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse request body",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	login, err := h.oAuthService.Exchange(r.Code, r.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrInvalidExchangeCode) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "INVALID_EXCHANGE_CODE",
+				"message": "Exchange code is invalid or expired",
+			})
+		}
+
+		log.Println("Cannot exchange code: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	token, err := h.startSession(c, login)
+	if err != nil {
+		log.Println("Cannot issue tokens: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(tokenJson(token))
+}
+
+// refreshTokenJson is the cookie-free counterpart of refreshToken for native
+// apps, the tokens are read from and returned in the JSON body.
+func (h *AuthHandler) refreshTokenJson(c *fiber.Ctx) error {
+	var r requests.RefreshTokenRequest
+	if err := c.BodyParser(&r); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse request body",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	authTokens, err := h.tokenService.RefreshToken(r.AccessToken, r.RefreshToken, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		if errors.Is(err, nerrors.ErrTokenStillValid) {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"code":    "TOKEN_STILL_VALID",
+				"message": "Token still valid",
+			})
+		}
+
+		if errors.Is(err, nerrors.ErrTokenReused) {
+			log.Println("Refresh token reuse detected, session revoked")
+		}
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":    "UNAUTHORIZE",
+			"message": "You are not authorized",
+		})
+	}
+
+	return c.JSON(tokenJson(authTokens))
+}
+
+func (h *AuthHandler) logout(c *fiber.Ctx) error {
+	refreshTokenValue := c.Cookies("refreshToken")
+	if refreshTokenValue == "" {
+		// Native apps send the refresh token in the body
+		var r requests.LogoutRequest
+		if err := c.BodyParser(&r); err == nil {
+			refreshTokenValue = r.RefreshToken
+		}
+	}
+
+	if refreshTokenValue != "" {
+		err := h.tokenService.RevokeRefreshToken(refreshTokenValue)
+		if err != nil && !errors.Is(err, nerrors.ErrTokenNotFound) {
+			log.Println("Cannot revoke refresh token: ", err)
+		}
+	}
 
 	cookie, err := configs.NewCookie()
 	if err != nil {
@@ -165,7 +418,7 @@ func (h *GoogleAuthHandler) logout(c *fiber.Ctx) error {
 	})
 }
 
-func (h *GoogleAuthHandler) refreshToken(c *fiber.Ctx) error {
+func (h *AuthHandler) refreshToken(c *fiber.Ctx) error {
 	accessToken := c.Cookies("accessToken")
 	refreshToken := c.Cookies("refreshToken")
 
@@ -176,7 +429,7 @@ func (h *GoogleAuthHandler) refreshToken(c *fiber.Ctx) error {
 		})
 	}
 
-	authTokens, err := h.tokenService.RefreshToken(accessToken, refreshToken)
+	authTokens, err := h.tokenService.RefreshToken(accessToken, refreshToken, c.Get(fiber.HeaderUserAgent), c.IP())
 	if err != nil {
 		if errors.Is(err, nerrors.ErrTokenStillValid) {
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -184,6 +437,11 @@ func (h *GoogleAuthHandler) refreshToken(c *fiber.Ctx) error {
 				"message": "Token still valid",
 			})
 		}
+
+		if errors.Is(err, nerrors.ErrTokenReused) {
+			log.Println("Refresh token reuse detected, session revoked")
+		}
+
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"code":    "UNAUTHORIZE",
 			"message": "You are not authorized",
@@ -217,3 +475,75 @@ func (h *GoogleAuthHandler) refreshToken(c *fiber.Ctx) error {
 		"message": "Refresh token success",
 	})
 }
+
+func (h *AuthHandler) getSessions(c *fiber.Ctx) error {
+	claims := c.Locals("token").(middleware.AccessToken)
+
+	sessions, err := h.tokenService.GetSessions(claims.Email, claims.SessionId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(sessions)
+}
+
+func (h *AuthHandler) revokeSession(c *fiber.Ctx) error {
+	claims := c.Locals("token").(middleware.AccessToken)
+
+	err := h.tokenService.RevokeSession(claims.Email, c.Params("id"))
+	if err != nil {
+		if errors.Is(err, nerrors.ErrTokenNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "SESSION_NOT_FOUND",
+				"message": "Session not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "Session revoked",
+	})
+}
+
+func (h *AuthHandler) revokeAllSessions(c *fiber.Ctx) error {
+	claims := c.Locals("token").(middleware.AccessToken)
+
+	err := h.tokenService.RevokeAllSessions(claims.Email)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "All sessions revoked",
+	})
+}
+
+func (h *AuthHandler) forceLogout(c *fiber.Ctx) error {
+	email := c.Params("email")
+
+	err := h.tokenService.ForceLogout(auditActor(c), email)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "User logged out from all sessions",
+	})
+}
@@ -0,0 +1,194 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/gofiber/fiber/v2"
+)
+
+func registrationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, nerrors.ErrCannotParseUUID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Cannot parse uuid",
+		})
+	case errors.Is(err, nerrors.ErrEventNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "EVENT_NOT_FOUND",
+			"message": "Event not found",
+		})
+	case errors.Is(err, nerrors.ErrRegistrationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":    "REGISTRATION_NOT_FOUND",
+			"message": "Registration not found",
+		})
+	case errors.Is(err, nerrors.ErrInvalidRegistrationList):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REGISTRATION_LIST",
+			"message": err.Error(),
+		})
+	case errors.Is(err, nerrors.ErrInvalidRegistrationMode):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REGISTRATION_MODE",
+			"message": "Mode must be open, registered_only or warn_unregistered",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+}
+
+func (h *eventHandler) getRegistrations(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+	search := c.Query("search")
+	pageIndex := c.Query("pageIndex", "0")
+	pageSize := c.Query("pageSize", "20")
+
+	registrations, err := h.registrationService.GetPagination(eventId, search, pageIndex, pageSize)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	count, err := h.registrationService.Count(eventId, search)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"registrations": registrations,
+		"totalRows":     count,
+	})
+}
+
+func (h *eventHandler) getRegistrationSummary(c *fiber.Ctx) error {
+	summary, err := h.registrationService.GetSummary(c.Params("id"))
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.JSON(summary)
+}
+
+func (h *eventHandler) createRegistrations(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	var r requests.RegistrationRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	count, err := h.registrationService.Create(eventId, auditActor(c), &r)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code":       "SUCCESS",
+		"registered": count,
+	})
+}
+
+func (h *eventHandler) importRegistrations(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "File is required",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+	defer file.Close()
+
+	rows, err := libs.ReadSpreadsheet(fileHeader.Filename, file)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrUnsupportedFileFormat) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "UNSUPPORTED_FILE_FORMAT",
+				"message": "Only CSV and XLSX files are supported",
+			})
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_FILE",
+			"message": "Cannot read file",
+		})
+	}
+
+	count, err := h.registrationService.Import(eventId, auditActor(c), fileHeader.Filename, rows)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"code":       "SUCCESS",
+		"registered": count,
+	})
+}
+
+func (h *eventHandler) deleteRegistration(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+	studentId := c.Params("studentId")
+
+	err := h.registrationService.Delete(eventId, auditActor(c), studentId)
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "Registration removed",
+	})
+}
+
+func (h *eventHandler) setRegistrationMode(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var r requests.RegistrationModeRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	err = h.eventService.SetRegistrationMode(id, auditActor(c), entities.RegistrationMode(r.Mode))
+	if err != nil {
+		return registrationError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"code":             "SUCCESS",
+		"registrationMode": r.Mode,
+	})
+}
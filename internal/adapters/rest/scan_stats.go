@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxDeviceIdLength = 100
+
+// scanActor reads the scanning staff member from the access token, or the
+// kiosk API key, and the scanner device from the X-Device-Id header.
+func scanActor(c *fiber.Ctx) (*entities.ScanActor, bool) {
+	deviceId := c.Get("X-Device-Id")
+
+	if len(deviceId) > maxDeviceIdLength {
+		return nil, false
+	}
+
+	if apiKey, ok := apiKeyActor(c); ok {
+		return &entities.ScanActor{
+			Actor:    entities.Actor{Ip: c.IP()},
+			DeviceId: deviceId,
+			ApiKeyId: &apiKey.Id,
+		}, true
+	}
+
+	return &entities.ScanActor{
+		Actor:    *auditActor(c),
+		DeviceId: deviceId,
+	}, true
+}
+
+func (h *eventHandler) getStaffScanCounts(c *fiber.Ctx) error {
+	counts, err := h.participantService.GetStaffScanCounts(c.Params("id"))
+	if err != nil {
+		if errors.Is(err, nerrors.ErrCannotParseUUID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"staffs": counts,
+	})
+}
+
+func (h *eventHandler) getStaffScanTimeline(c *fiber.Ctx) error {
+	interval := c.Query("interval", "hour")
+
+	buckets, err := h.participantService.GetStaffScanTimeline(c.Params("id"), interval)
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrCannotParseUUID):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		case errors.Is(err, nerrors.ErrInvalidTimelineInterval):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_TIMELINE_INTERVAL",
+				"message": "Interval must be minute, hour or day",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"interval": interval,
+		"timeline": buckets,
+	})
+}
@@ -0,0 +1,186 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/requests"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *eventHandler) getSessions(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	sessions, err := h.sessionService.GetAllFromEventId(eventId)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrCannotParseUUID) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"sessions": sessions,
+	})
+}
+
+func (h *eventHandler) getSessionAttendance(c *fiber.Ctx) error {
+	pageIndex := c.Query("pageIndex")
+	pageSize := c.Query("pageSize")
+	search := c.Query("search")
+	eventId := c.Params("id")
+
+	attendance, err := h.sessionService.GetAttendance(eventId, search, pageIndex, pageSize)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": "Internal server error",
+		})
+	}
+
+	count, err := h.sessionService.CountAttendance(eventId, search)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INTERNAL_SERVER_ERROR",
+			"message": "Internal server error",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"attendance": attendance,
+		"totalRows":  count,
+	})
+}
+
+func (h *eventHandler) createSession(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+
+	var r requests.SessionRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	session, err := h.sessionService.Create(eventId, auditActor(c), &r)
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrEventNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "EVENT_NOT_FOUND",
+				"message": "Event not found",
+			})
+		case errors.Is(err, nerrors.ErrInvalidSessionWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_SESSION_WINDOW",
+				"message": "Session end is before start",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"session": session,
+	})
+}
+
+func (h *eventHandler) updateSession(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+	sessionId := c.Params("sessionId")
+
+	var r requests.SessionRequest
+	err := c.BodyParser(&r)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "Invalid request",
+		})
+	}
+
+	errs := libs.Validator.Validate(r)
+	if errs != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	err = h.sessionService.UpdateById(eventId, sessionId, auditActor(c), &r)
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrCannotParseUUID):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		case errors.Is(err, nerrors.ErrSessionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "SESSION_NOT_FOUND",
+				"message": "Session not found",
+			})
+		case errors.Is(err, nerrors.ErrInvalidSessionWindow):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_SESSION_WINDOW",
+				"message": "Session end is before start",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "Session updated successfully",
+	})
+}
+
+func (h *eventHandler) deleteSession(c *fiber.Ctx) error {
+	eventId := c.Params("id")
+	sessionId := c.Params("sessionId")
+
+	err := h.sessionService.DeleteById(eventId, sessionId, auditActor(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, nerrors.ErrCannotParseUUID):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_REQUEST",
+				"message": "Cannot parse uuid",
+			})
+		case errors.Is(err, nerrors.ErrSessionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "SESSION_NOT_FOUND",
+				"message": "Session not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":    "SUCCESS",
+		"message": "Delete Session Success",
+	})
+}
@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type studentHandler struct {
+	app     *fiber.App
+	service services.StudentService
+}
+
+func NewStudentHandler(app *fiber.App, service services.StudentService) {
+	handler := &studentHandler{
+		app:     app,
+		service: service,
+	}
+
+	student := app.Group("/students", middleware.Jwt, middleware.AdminMiddleware)
+
+	student.Get("/", handler.getPagination)
+	student.Get("/:studentId", handler.getById)
+	student.Get("/:barcode/attendances", handler.getAttendances)
+	student.Post("/import", handler.importRoster)
+}
+
+func (h *studentHandler) getPagination(c *fiber.Ctx) error {
+	search := c.Query("search")
+	pageIndex := c.Query("pageIndex")
+	pageSize := c.Query("pageSize")
+
+	students, err := h.service.GetPagination(search, pageIndex, pageSize)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	count, err := h.service.Count(search)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"students":  students,
+		"totalRows": count,
+	})
+}
+
+func (h *studentHandler) getById(c *fiber.Ctx) error {
+	student, err := h.service.GetById(c.Params("studentId"))
+	if err != nil {
+		if errors.Is(err, nerrors.ErrStudentNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":    "STUDENT_NOT_FOUND",
+				"message": "Student not found",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(student)
+}
+
+func (h *studentHandler) importRoster(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_REQUEST",
+			"message": "File is required",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+	defer file.Close()
+
+	rows, err := libs.ReadSpreadsheet(fileHeader.Filename, file)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrUnsupportedFileFormat) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "UNSUPPORTED_FILE_FORMAT",
+				"message": "Only CSV and XLSX files are supported",
+			})
+		}
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":    "INVALID_FILE",
+			"message": "Cannot read file",
+		})
+	}
+
+	count, err := h.service.Import(auditActor(c), fileHeader.Filename, rows)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrInvalidStudentRoster) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_STUDENT_ROSTER",
+				"message": err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"code":     "SUCCESS",
+		"imported": count,
+	})
+}
+
+func (h *studentHandler) getAttendances(c *fiber.Ctx) error {
+	barcode := c.Params("barcode")
+	from := c.Query("from")
+	to := c.Query("to")
+	pageIndex := c.Query("pageIndex", "0")
+	pageSize := c.Query("pageSize", "20")
+
+	attendances, err := h.service.GetAttendances(barcode, from, to, pageIndex, pageSize)
+	if err != nil {
+		if errors.Is(err, nerrors.ErrInvalidDateRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":    "INVALID_DATE_RANGE",
+				"message": "Dates must be YYYY-MM-DD and from must not be after to",
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	summary, err := h.service.GetAttendanceSummary(barcode, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":    "SOMETHING_WENT_WRONG",
+			"message": "Something went wrong",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"attendances":       attendances,
+		"totalRows":         summary.TotalEvents,
+		"totalEvents":       summary.TotalEvents,
+		"totalDwellSeconds": summary.TotalDwellSeconds,
+	})
+}
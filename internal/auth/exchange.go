@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
+	"golang.org/x/oauth2"
+)
+
+const exchangeCodeTTL = time.Minute
+
+// exchangeStore hands a finished native login to the app. The code travels
+// through the app's redirect URI, so it is short lived, can only be used once
+// and is bound to the PKCE challenge the app started with. It carries the
+// user and session only, the tokens are issued once the code is redeemed.
+type exchangeStore struct {
+	repo repositories.OAuthRepository
+}
+
+func newExchangeStore(repo repositories.OAuthRepository) *exchangeStore {
+	return &exchangeStore{
+		repo: repo,
+	}
+}
+
+func (s *exchangeStore) create(login *services.OAuthLogin, codeChallenge string) (string, error) {
+	code, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.CreateExchangeCode(&entities.ExchangeCode{
+		CodeHash:      libs.HashToken(code),
+		CodeChallenge: codeChallenge,
+		SessionId:     login.SessionId,
+		Email:         login.Email,
+		Name:          login.Name,
+		Picture:       login.Picture,
+		Role:          login.Role,
+		ExpiresAt:     time.Now().Add(exchangeCodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// take removes the code and returns its login only when codeVerifier matches
+// the S256 challenge it was created with.
+func (s *exchangeStore) take(code string, codeVerifier string) (*services.OAuthLogin, error) {
+	if code == "" {
+		return nil, nerrors.ErrInvalidExchangeCode
+	}
+
+	entry, err := s.repo.TakeExchangeCode(libs.HashToken(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		return nil, nerrors.ErrInvalidExchangeCode
+	}
+
+	challenge := oauth2.S256ChallengeFromVerifier(codeVerifier)
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(entry.CodeChallenge)) != 1 {
+		return nil, nerrors.ErrInvalidExchangeCode
+	}
+
+	return &services.OAuthLogin{
+		SessionId: entry.SessionId,
+		Email:     entry.Email,
+		Name:      entry.Name,
+		Picture:   entry.Picture,
+		Role:      entry.Role,
+	}, nil
+}
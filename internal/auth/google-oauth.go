@@ -3,77 +3,59 @@ package auth
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"log"
+	"net/http"
 	"os"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
-	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
-	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
-	"github.com/google/uuid"
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
 type GooglePayload struct {
-	Email   string `json:"email"`
-	Name    string `json:"name"`
-	Picture string `json:"picture"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
 }
 
-type googleOAuthService struct {
-	c            *oauth2.Config
-	states       map[string]bool
-	adminService services.AdminService
-	staffService services.StaffService
+type googleProvider struct {
+	c *oauth2.Config
 }
 
-func NewGoogleOAuth(adminService services.AdminService, staffService services.StaffService) services.OAuthService {
+func NewGoogleProvider() Provider {
 	conf := &oauth2.Config{
 		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
 		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
 		RedirectURL:  fmt.Sprintf("%s/auth/google/callback", os.Getenv("API_URL")),
 		Scopes: []string{
+			"openid",
 			"https://www.googleapis.com/auth/userinfo.email",
 			"https://www.googleapis.com/auth/userinfo.profile",
 		},
 		Endpoint: google.Endpoint,
 	}
 
-	return &googleOAuthService{
-		c:            conf,
-		states:       make(map[string]bool),
-		adminService: adminService,
-		staffService: staffService,
+	return &googleProvider{
+		c: conf,
 	}
 }
 
-func generateState() (*string, error) {
-	gen, err := uuid.NewV7()
-	if err != nil {
-		return nil, err
-	}
-
-	strGen := gen.String()
-
-	return &strGen, nil
+func (p *googleProvider) Name() string {
+	return "google"
 }
 
-func (s *googleOAuthService) Auth() (*string, error) {
-	state, err := generateState()
-	if err != nil {
-		return nil, err
-	}
-
-	url := s.c.AuthCodeURL(*state)
-
-	return &url, nil
+func (p *googleProvider) AuthCodeURL(state string, verifier string, nonce string) (string, error) {
+	return p.c.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
 }
 
-func (s *googleOAuthService) getUserInfo(ctx context.Context, tok *oauth2.Token) (*GooglePayload, error) {
-	client := s.c.Client(ctx, tok)
+func (p *googleProvider) getUserInfo(ctx context.Context, tok *oauth2.Token) (*GooglePayload, error) {
+	client := p.c.Client(ctx, tok)
 	res, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
 	if err != nil {
 		return nil, err
@@ -81,6 +63,10 @@ func (s *googleOAuthService) getUserInfo(ctx context.Context, tok *oauth2.Token)
 
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo returned status %d", res.StatusCode)
+	}
+
 	body, err := io.ReadAll(res.Body)
 
 	if err != nil {
@@ -95,78 +81,51 @@ func (s *googleOAuthService) getUserInfo(ctx context.Context, tok *oauth2.Token)
 	return &googlePayload, nil
 }
 
-func (s *googleOAuthService) getRole(email string) (*string, error) {
-	admin, err := s.adminService.GetByEmail(email)
-	if err != nil {
-		if !errors.Is(err, nerrors.ErrAdminNotFound) {
-			return nil, err
-		}
+// verifyNonce checks the nonce of the ID token returned with tok. The token
+// comes straight from the token endpoint over TLS so its signature is not
+// checked here.
+func (p *googleProvider) verifyNonce(tok *oauth2.Token, nonce string) error {
+	rawIdToken, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return nerrors.ErrInvalidOAuthNonce
 	}
 
-	var role string
-
-	if admin != nil {
-		role = "admin"
-		return &role, nil
-	}
-
-	staffs, err := s.staffService.GetByEmail(email)
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(rawIdToken, &claims)
 	if err != nil {
-		return nil, err
+		return nerrors.ErrInvalidOAuthNonce
 	}
 
-	if len(staffs) > 0 {
-		role = "staff"
-		return &role, nil
+	if claims["nonce"] != nonce {
+		return nerrors.ErrInvalidOAuthNonce
 	}
 
-	return nil, nerrors.ErrUserNotFound
+	return nil
 }
 
-func (s *googleOAuthService) Callback(code string, state string) (*string, *services.AuthToken, error) {
-
-	if s.states[state] {
-		return nil, nil, nerrors.ErrTokenNotFound
-	}
-
-	delete(s.states, state)
-
-	ctx := context.Background()
-
-	tok, err := s.c.Exchange(ctx, code)
+func (p *googleProvider) Identity(ctx context.Context, code string, verifier string, nonce string) (*Identity, error) {
+	tok, err := p.c.Exchange(ctx, code, oauth2.VerifierOption(verifier))
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("%w: %v", nerrors.ErrOAuthExchange, err)
 	}
 
-	payload, err := s.getUserInfo(ctx, tok)
+	err = p.verifyNonce(tok, nonce)
 	if err != nil {
-		return nil, nil, nerrors.ErrSomethingWentWrong
-	}
-
-	role, err := s.getRole(payload.Email)
-	if err != nil {
-		if !errors.Is(err, nerrors.ErrUserNotFound) {
-			err = nerrors.ErrSomethingWentWrong
-		}
-
-		return nil, nil, err
+		return nil, err
 	}
 
-	accessToken, accessExp, err := libs.GenerateAccessToken(payload.Email, payload.Name, payload.Picture, *role)
+	payload, err := p.getUserInfo(ctx, tok)
 	if err != nil {
-		return nil, nil, err
+		return nil, fmt.Errorf("%w: %v", nerrors.ErrSomethingWentWrong, err)
 	}
 
-	refreshToken, refreshExp, err := libs.GenerateRefreshToken(payload.Email)
-	if err != nil {
-		return nil, nil, err
+	if !payload.VerifiedEmail {
+		return nil, nerrors.ErrEmailNotVerified
 	}
 
-	return &payload.Email, &services.AuthToken{
-		AccessToken:         *accessToken,
-		AccessTokenExpired:  *accessExp,
-		RefreshToken:        *refreshToken,
-		RefreshTokenExpired: *refreshExp,
+	return &Identity{
+		Email:   payload.Email,
+		Name:    payload.Name,
+		Picture: payload.Picture,
 	}, nil
-
 }
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type oAuthService struct {
+	providers            map[string]Provider
+	states               *stateStore
+	exchanges            *exchangeStore
+	domains              EmailDomains
+	adminService         services.AdminService
+	staffService         services.StaffService
+	accessRequestService services.AccessRequestService
+}
+
+// parseDomains reads a comma separated list such as "ku.th,ku.ac.th".
+func parseDomains(value string) []string {
+	var domains []string
+	for _, domain := range strings.Split(value, ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// EmailDomains limits who can sign in by the domain of their email. Logins
+// are refused while Allowed is empty unless AllowAny is set.
+type EmailDomains struct {
+	Allowed  []string
+	AllowAny bool
+}
+
+// EmailDomainsFromEnv reads ALLOWED_EMAIL_DOMAINS and ALLOW_ANY_EMAIL_DOMAIN.
+// Leaving both unset is an error so a missing domain list cannot open sign
+// in to every account of a provider.
+func EmailDomainsFromEnv() (EmailDomains, error) {
+	domains := EmailDomains{
+		Allowed: parseDomains(os.Getenv("ALLOWED_EMAIL_DOMAINS")),
+	}
+
+	if value := os.Getenv("ALLOW_ANY_EMAIL_DOMAIN"); value != "" {
+		allowAny, err := strconv.ParseBool(value)
+		if err != nil {
+			return domains, fmt.Errorf("invalid ALLOW_ANY_EMAIL_DOMAIN %q", value)
+		}
+		domains.AllowAny = allowAny
+	}
+
+	if len(domains.Allowed) == 0 && !domains.AllowAny {
+		return domains, errors.New("ALLOWED_EMAIL_DOMAINS is not set, set ALLOW_ANY_EMAIL_DOMAIN=true to allow every domain")
+	}
+
+	return domains, nil
+}
+
+// NewOAuthService signs users in through providers whose email matches
+// domains. Pending logins and exchange codes are kept in repo so any instance
+// can finish them.
+func NewOAuthService(repo repositories.OAuthRepository, domains EmailDomains, adminService services.AdminService, staffService services.StaffService, accessRequestService services.AccessRequestService, providers ...Provider) services.OAuthService {
+	registry := make(map[string]Provider, len(providers))
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+
+	return &oAuthService{
+		providers:            registry,
+		states:               newStateStore(repo),
+		exchanges:            newExchangeStore(repo),
+		domains:              domains,
+		adminService:         adminService,
+		staffService:         staffService,
+		accessRequestService: accessRequestService,
+	}
+}
+
+// isDomainAllowed reports whether email belongs to one of the allowed domains
+// or their subdomains. Without any allowed domain only AllowAny lets it in.
+func (s *oAuthService) isDomainAllowed(email string) bool {
+	if len(s.domains.Allowed) == 0 {
+		return s.domains.AllowAny
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range s.domains.Allowed {
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *oAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (s *oAuthService) Auth(provider string, binding string) (*string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, nerrors.ErrOAuthProviderNotFound
+	}
+
+	if binding == "" {
+		return nil, nerrors.ErrInvalidOAuthState
+	}
+
+	verifier := oauth2.GenerateVerifier()
+
+	entry, err := s.states.create(provider, binding, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := p.AuthCodeURL(entry.State, verifier, entry.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+func (s *oAuthService) Callback(provider string, code string, state string, binding string) (*services.OAuthLogin, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, nerrors.ErrOAuthProviderNotFound
+	}
+
+	entry, err := s.states.take(state, provider, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := p.Identity(context.Background(), code, entry.Verifier, entry.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity.Email == "" {
+		return nil, fmt.Errorf("%w: missing email", nerrors.ErrSomethingWentWrong)
+	}
+
+	if !s.isDomainAllowed(identity.Email) {
+		return nil, nerrors.ErrEmailDomainNotAllowed
+	}
+
+	role, err := services.ResolveRole(s.adminService, s.staffService, identity.Email)
+	if err != nil {
+		if !errors.Is(err, nerrors.ErrUserNotFound) {
+			return nil, nerrors.ErrSomethingWentWrong
+		}
+
+		err = s.accessRequestService.Request(identity.Email, identity.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, nerrors.ErrAccessRequestPending
+	}
+
+	sessionId, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.OAuthLogin{
+		SessionId: sessionId.String(),
+		Email:     identity.Email,
+		Name:      identity.Name,
+		Picture:   identity.Picture,
+		Role:      *role,
+	}, nil
+}
+
+func (s *oAuthService) CreateExchangeCode(login *services.OAuthLogin, codeChallenge string) (string, error) {
+	if codeChallenge == "" {
+		return "", nerrors.ErrInvalidExchangeCode
+	}
+
+	return s.exchanges.create(login, codeChallenge)
+}
+
+func (s *oAuthService) Exchange(code string, codeVerifier string) (*services.OAuthLogin, error) {
+	return s.exchanges.take(code, codeVerifier)
+}
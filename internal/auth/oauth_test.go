@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
+	"golang.org/x/oauth2"
+)
+
+type fakeOAuthRepo struct {
+	mu     sync.Mutex
+	states map[string]entities.OAuthState
+	codes  map[string]entities.ExchangeCode
+}
+
+func newFakeOAuthRepo() *fakeOAuthRepo {
+	return &fakeOAuthRepo{
+		states: map[string]entities.OAuthState{},
+		codes:  map[string]entities.ExchangeCode{},
+	}
+}
+
+func (r *fakeOAuthRepo) CreateState(state *entities.OAuthState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states[state.State] = *state
+	return nil
+}
+
+func (r *fakeOAuthRepo) TakeState(state string) (*entities.OAuthState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.states[state]
+	if !ok {
+		return nil, nerrors.ErrInvalidOAuthState
+	}
+
+	delete(r.states, state)
+	return &entry, nil
+}
+
+func (r *fakeOAuthRepo) CreateExchangeCode(code *entities.ExchangeCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codes[code.CodeHash] = *code
+	return nil
+}
+
+func (r *fakeOAuthRepo) TakeExchangeCode(codeHash string) (*entities.ExchangeCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.codes[codeHash]
+	if !ok {
+		return nil, nerrors.ErrInvalidExchangeCode
+	}
+
+	delete(r.codes, codeHash)
+	return &entry, nil
+}
+
+func (r *fakeOAuthRepo) DeleteExpired() error {
+	return nil
+}
+
+func TestExchangeCode(t *testing.T) {
+	repo := newFakeOAuthRepo()
+	s := &oAuthService{exchanges: newExchangeStore(repo)}
+
+	verifier := oauth2.GenerateVerifier()
+	login := &services.OAuthLogin{
+		SessionId: "session",
+		Email:     "user@ku.th",
+		Name:      "User",
+		Picture:   "https://example.com/user.png",
+		Role:      "staff",
+	}
+
+	code, err := s.CreateExchangeCode(login, oauth2.S256ChallengeFromVerifier(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := repo.codes[code]; ok {
+		t.Error("exchange code is stored in plain text")
+	}
+
+	exchanged, err := s.Exchange(code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *exchanged != *login {
+		t.Errorf("login = %+v, want %+v", *exchanged, *login)
+	}
+
+	_, err = s.Exchange(code, verifier)
+	if !errors.Is(err, nerrors.ErrInvalidExchangeCode) {
+		t.Errorf("reused code: err = %v, want ErrInvalidExchangeCode", err)
+	}
+}
+
+func TestExchangeCodeRejectsVerifier(t *testing.T) {
+	repo := newFakeOAuthRepo()
+	s := &oAuthService{exchanges: newExchangeStore(repo)}
+
+	code, err := s.CreateExchangeCode(&services.OAuthLogin{}, oauth2.S256ChallengeFromVerifier(oauth2.GenerateVerifier()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Exchange(code, oauth2.GenerateVerifier())
+	if !errors.Is(err, nerrors.ErrInvalidExchangeCode) {
+		t.Errorf("err = %v, want ErrInvalidExchangeCode", err)
+	}
+
+	if len(repo.codes) != 0 {
+		t.Error("code survived a failed exchange")
+	}
+}
+
+func TestExchangeCodeExpired(t *testing.T) {
+	repo := newFakeOAuthRepo()
+	s := &oAuthService{exchanges: newExchangeStore(repo)}
+
+	verifier := oauth2.GenerateVerifier()
+	code, err := s.CreateExchangeCode(&services.OAuthLogin{}, oauth2.S256ChallengeFromVerifier(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for hash, entry := range repo.codes {
+		entry.ExpiresAt = time.Now().Add(-time.Second)
+		repo.codes[hash] = entry
+	}
+
+	_, err = s.Exchange(code, verifier)
+	if !errors.Is(err, nerrors.ErrInvalidExchangeCode) {
+		t.Errorf("err = %v, want ErrInvalidExchangeCode", err)
+	}
+}
+
+func TestEmailDomainsFromEnv(t *testing.T) {
+	t.Setenv("ALLOWED_EMAIL_DOMAINS", "")
+	t.Setenv("ALLOW_ANY_EMAIL_DOMAIN", "")
+
+	if _, err := EmailDomainsFromEnv(); err == nil {
+		t.Error("empty ALLOWED_EMAIL_DOMAINS was accepted")
+	}
+
+	t.Setenv("ALLOW_ANY_EMAIL_DOMAIN", "true")
+
+	domains, err := EmailDomainsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !domains.AllowAny {
+		t.Error("ALLOW_ANY_EMAIL_DOMAIN was ignored")
+	}
+
+	t.Setenv("ALLOWED_EMAIL_DOMAINS", "ku.th, KU.AC.TH")
+	t.Setenv("ALLOW_ANY_EMAIL_DOMAIN", "")
+
+	domains, err = EmailDomainsFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(domains.Allowed) != 2 || domains.Allowed[1] != "ku.ac.th" {
+		t.Errorf("allowed = %v, want [ku.th ku.ac.th]", domains.Allowed)
+	}
+}
+
+func TestIsDomainAllowed(t *testing.T) {
+	tests := []struct {
+		domains EmailDomains
+		email   string
+		want    bool
+	}{
+		{EmailDomains{}, "user@ku.th", false},
+		{EmailDomains{AllowAny: true}, "user@gmail.com", true},
+		{EmailDomains{Allowed: []string{"ku.th"}}, "user@ku.th", true},
+		{EmailDomains{Allowed: []string{"ku.th"}}, "user@cpe.ku.th", true},
+		{EmailDomains{Allowed: []string{"ku.th"}}, "user@notku.th", false},
+		{EmailDomains{Allowed: []string{"ku.th"}, AllowAny: true}, "user@gmail.com", false},
+	}
+
+	for _, tt := range tests {
+		s := &oAuthService{domains: tt.domains}
+		if got := s.isDomainAllowed(tt.email); got != tt.want {
+			t.Errorf("isDomainAllowed(%q) with %+v = %v, want %v", tt.email, tt.domains, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type OIDCConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	EmailClaim   string
+	NameClaim    string
+	PictureClaim string
+	// TrustUnverifiedEmail accepts logins whose email_verified claim is
+	// missing or false. Only enable it for issuers that verify every email
+	// they hand out without saying so.
+	TrustUnverifiedEmail bool
+}
+
+// OIDCConfigFromEnv reads the generic OpenID Connect provider from the
+// OIDC_* variables. It returns nil when OIDC_ISSUER_URL is not set.
+func OIDCConfigFromEnv() *OIDCConfig {
+	issuer := os.Getenv("OIDC_ISSUER_URL")
+	if issuer == "" {
+		return nil
+	}
+
+	name := envOrDefault("OIDC_PROVIDER_NAME", "oidc")
+
+	return &OIDCConfig{
+		Name:                 name,
+		IssuerURL:            issuer,
+		ClientID:             os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:         os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:          fmt.Sprintf("%s/auth/%s/callback", os.Getenv("API_URL"), name),
+		Scopes:               strings.Fields(envOrDefault("OIDC_SCOPES", "openid email profile")),
+		EmailClaim:           envOrDefault("OIDC_EMAIL_CLAIM", "email"),
+		NameClaim:            envOrDefault("OIDC_NAME_CLAIM", "name"),
+		PictureClaim:         envOrDefault("OIDC_PICTURE_CLAIM", "picture"),
+		TrustUnverifiedEmail: os.Getenv("OIDC_TRUST_UNVERIFIED_EMAIL") == "true",
+	}
+}
+
+func envOrDefault(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+type oidcProvider struct {
+	config   OIDCConfig
+	mu       sync.Mutex
+	provider *oidc.Provider
+	c        *oauth2.Config
+}
+
+// NewOIDCProvider creates a provider for any OpenID Connect issuer. The
+// discovery document is fetched on first use so the server can start while
+// the issuer is unreachable.
+func NewOIDCProvider(config OIDCConfig) Provider {
+	return &oidcProvider{
+		config: config,
+	}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, *oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.provider, p.c, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.config.IssuerURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.provider = provider
+	p.c = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint:     provider.Endpoint(),
+	}
+
+	return p.provider, p.c, nil
+}
+
+func (p *oidcProvider) AuthCodeURL(state string, verifier string, nonce string) (string, error) {
+	_, c, err := p.discover(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	return c.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oidc.Nonce(nonce),
+	), nil
+}
+
+func claimString(claims map[string]any, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// claimBool reads a boolean claim. Some issuers send booleans as strings.
+func claimBool(claims map[string]any, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+
+	return false
+}
+
+func (p *oidcProvider) Identity(ctx context.Context, code string, verifier string, nonce string) (*Identity, error) {
+	provider, c, err := p.discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", nerrors.ErrOAuthExchange, err)
+	}
+
+	tok, err := c.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", nerrors.ErrOAuthExchange, err)
+	}
+
+	rawIdToken, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing id_token", nerrors.ErrOAuthExchange)
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.config.ClientID}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", nerrors.ErrOAuthExchange, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, nerrors.ErrInvalidOAuthNonce
+	}
+
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	// Some issuers leave profile claims out of the ID token, fall back to the
+	// userinfo endpoint for the missing ones
+	if claimString(claims, p.config.EmailClaim) == "" {
+		userInfo, err := provider.UserInfo(ctx, oauth2.StaticTokenSource(tok))
+		if err != nil {
+			return nil, err
+		}
+
+		extra := map[string]any{}
+		if err := userInfo.Claims(&extra); err != nil {
+			return nil, err
+		}
+
+		for key, value := range extra {
+			if _, ok := claims[key]; !ok {
+				claims[key] = value
+			}
+		}
+	}
+
+	// Anyone can put an address they do not own on an account at most
+	// issuers, so only verified ones are trusted to pick the user
+	if !p.config.TrustUnverifiedEmail && !claimBool(claims, "email_verified") {
+		return nil, nerrors.ErrEmailNotVerified
+	}
+
+	return &Identity{
+		Email:   claimString(claims, p.config.EmailClaim),
+		Name:    claimString(claims, p.config.NameClaim),
+		Picture: claimString(claims, p.config.PictureClaim),
+	}, nil
+}
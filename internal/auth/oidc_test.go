@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "nisit-scan"
+	testKid      = "test-key"
+)
+
+// fakeIssuer is an OpenID Connect issuer serving discovery, JWKS and token
+// endpoints. Codes are issued through authorize, which records the PKCE
+// challenge and nonce of the login like a real authorization endpoint.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu            sync.Mutex
+	challenges    map[string]string
+	nonces        map[string]string
+	nonceOverride string
+	emailVerified any
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &fakeIssuer{
+		key:           key,
+		challenges:    map[string]string{},
+		nonces:        map[string]string{},
+		emailVerified: true,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/token", issuer.token)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (f *fakeIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                f.server.URL,
+		"authorization_endpoint":                f.server.URL + "/authorize",
+		"token_endpoint":                        f.server.URL + "/token",
+		"jwks_uri":                              f.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (f *fakeIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &f.key.PublicKey,
+			KeyID:     testKid,
+			Algorithm: "RS256",
+			Use:       "sig",
+		}},
+	})
+}
+
+// authorize plays the user approving the login at authURL and returns the
+// code the issuer redirects back with.
+func (f *fakeIssuer) authorize(t *testing.T, authURL string) (code string, state string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("auth url %s does not use PKCE", authURL)
+	}
+
+	code = "code-" + query.Get("state")
+
+	f.mu.Lock()
+	f.challenges[code] = query.Get("code_challenge")
+	f.nonces[code] = query.Get("nonce")
+	f.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (f *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	f.mu.Lock()
+	challenge, ok := f.challenges[code]
+	nonce := f.nonces[code]
+	delete(f.challenges, code)
+	if f.nonceOverride != "" {
+		nonce = f.nonceOverride
+	}
+	emailVerified := f.emailVerified
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            f.server.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          "user@example.com",
+		"email_verified": emailVerified,
+		"name":           "User",
+	})
+	idToken.Header["kid"] = testKid
+
+	signed, err := idToken.SignedString(f.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func newTestOIDCService(t *testing.T, config func(*OIDCConfig)) (*oAuthService, *fakeOAuthRepo, *fakeIssuer) {
+	t.Helper()
+
+	issuer := newFakeIssuer(t)
+
+	oidcConfig := OIDCConfig{
+		Name:         "oidc",
+		IssuerURL:    issuer.server.URL,
+		ClientID:     testClientID,
+		RedirectURL:  "http://localhost/auth/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+		EmailClaim:   "email",
+		NameClaim:    "name",
+		PictureClaim: "picture",
+	}
+	if config != nil {
+		config(&oidcConfig)
+	}
+
+	repo := newFakeOAuthRepo()
+	s := &oAuthService{
+		providers: map[string]Provider{"oidc": NewOIDCProvider(oidcConfig)},
+		states:    newStateStore(repo),
+	}
+
+	return s, repo, issuer
+}
+
+// startLogin runs Auth and the approval at the issuer, returning what the
+// callback receives along with the pending state entry.
+func startLogin(t *testing.T, s *oAuthService, repo *fakeOAuthRepo, issuer *fakeIssuer, binding string) (string, string, entities.OAuthState) {
+	t.Helper()
+
+	authURL, err := s.Auth("oidc", binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, state := issuer.authorize(t, *authURL)
+
+	repo.mu.Lock()
+	entry := repo.states[state]
+	repo.mu.Unlock()
+
+	return code, state, entry
+}
+
+func TestOIDCIdentity(t *testing.T) {
+	s, repo, issuer := newTestOIDCService(t, nil)
+	code, _, entry := startLogin(t, s, repo, issuer, "browser")
+
+	identity, err := s.providers["oidc"].Identity(context.Background(), code, entry.Verifier, entry.Nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.Email != "user@example.com" || identity.Name != "User" {
+		t.Errorf("identity = %+v", identity)
+	}
+}
+
+func TestOIDCCallbackRejectsState(t *testing.T) {
+	s, repo, issuer := newTestOIDCService(t, nil)
+	code, state, _ := startLogin(t, s, repo, issuer, "browser")
+
+	_, err := s.Callback("oidc", code, "made-up", "browser")
+	if !errors.Is(err, nerrors.ErrInvalidOAuthState) {
+		t.Errorf("unknown state: err = %v, want ErrInvalidOAuthState", err)
+	}
+
+	_, err = s.Callback("oidc", code, state, "other-browser")
+	if !errors.Is(err, nerrors.ErrInvalidOAuthState) {
+		t.Errorf("other session: err = %v, want ErrInvalidOAuthState", err)
+	}
+
+	// The failed attempt above used the state up
+	_, err = s.Callback("oidc", code, state, "browser")
+	if !errors.Is(err, nerrors.ErrInvalidOAuthState) {
+		t.Errorf("reused state: err = %v, want ErrInvalidOAuthState", err)
+	}
+}
+
+func TestOIDCIdentityRejectsWrongVerifier(t *testing.T) {
+	s, repo, issuer := newTestOIDCService(t, nil)
+	code, _, entry := startLogin(t, s, repo, issuer, "browser")
+
+	_, err := s.providers["oidc"].Identity(context.Background(), code, "wrong-verifier", entry.Nonce)
+	if !errors.Is(err, nerrors.ErrOAuthExchange) {
+		t.Errorf("err = %v, want ErrOAuthExchange", err)
+	}
+}
+
+func TestOIDCCallbackRejectsNonce(t *testing.T) {
+	s, repo, issuer := newTestOIDCService(t, nil)
+	issuer.nonceOverride = "replayed-nonce"
+	code, state, _ := startLogin(t, s, repo, issuer, "browser")
+
+	_, err := s.Callback("oidc", code, state, "browser")
+	if !errors.Is(err, nerrors.ErrInvalidOAuthNonce) {
+		t.Errorf("err = %v, want ErrInvalidOAuthNonce", err)
+	}
+}
+
+func TestOIDCCallbackRejectsUnverifiedEmail(t *testing.T) {
+	for _, emailVerified := range []any{false, "false", nil} {
+		s, repo, issuer := newTestOIDCService(t, nil)
+		issuer.emailVerified = emailVerified
+		code, state, _ := startLogin(t, s, repo, issuer, "browser")
+
+		_, err := s.Callback("oidc", code, state, "browser")
+		if !errors.Is(err, nerrors.ErrEmailNotVerified) {
+			t.Errorf("email_verified = %v: err = %v, want ErrEmailNotVerified", emailVerified, err)
+		}
+	}
+}
+
+func TestOIDCTrustUnverifiedEmail(t *testing.T) {
+	s, repo, issuer := newTestOIDCService(t, func(c *OIDCConfig) {
+		c.TrustUnverifiedEmail = true
+	})
+	issuer.emailVerified = false
+	code, _, entry := startLogin(t, s, repo, issuer, "browser")
+
+	identity, err := s.providers["oidc"].Identity(context.Background(), code, entry.Verifier, entry.Nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.Email != "user@example.com" {
+		t.Errorf("email = %q, want user@example.com", identity.Email)
+	}
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+)
+
+type Identity struct {
+	Email   string
+	Name    string
+	Picture string
+}
+
+// Provider is an identity provider that can sign a user in through the
+// authorization code flow.
+type Provider interface {
+	Name() string
+	AuthCodeURL(state string, verifier string, nonce string) (string, error)
+	Identity(ctx context.Context, code string, verifier string, nonce string) (*Identity, error)
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+)
+
+const stateTTL = 10 * time.Minute
+
+// stateStore keeps pending OAuth logins keyed by state until the provider
+// redirects back. Each state is bound to the browser session that started
+// the login and can only be used once. States live in the database so the
+// callback can land on any instance.
+type stateStore struct {
+	repo repositories.OAuthRepository
+}
+
+func newStateStore(repo repositories.OAuthRepository) *stateStore {
+	return &stateStore{
+		repo: repo,
+	}
+}
+
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (s *stateStore) create(provider string, binding string, verifier string) (*entities.OAuthState, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abandoned logins and unredeemed exchange codes are cleared here, they
+	// are rejected once expired anyway
+	if err := s.repo.DeleteExpired(); err != nil {
+		log.Println("Cannot delete expired oauth states: ", err)
+	}
+
+	entry := &entities.OAuthState{
+		State:     state,
+		Provider:  provider,
+		Binding:   binding,
+		Verifier:  verifier,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(stateTTL),
+	}
+
+	err = s.repo.CreateState(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// take removes the state and returns it only when it is still valid for the
+// given provider and session binding.
+func (s *stateStore) take(state string, provider string, binding string) (*entities.OAuthState, error) {
+	if state == "" {
+		return nil, nerrors.ErrInvalidOAuthState
+	}
+
+	entry, err := s.repo.TakeState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if binding == "" || entry.Provider != provider || entry.Binding != binding || time.Now().After(entry.ExpiresAt) {
+		return nil, nerrors.ErrInvalidOAuthState
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,34 @@
+package libs
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// ApiKeyPrefix marks a bearer token as an API key rather than a JWT.
+const ApiKeyPrefix = "nsk_"
+
+// apiKeyDisplayLength is how much of a key is kept in the clear so admins can
+// tell keys apart.
+const apiKeyDisplayLength = 12
+
+func GenerateApiKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return ApiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func IsApiKey(token string) bool {
+	return strings.HasPrefix(token, ApiKeyPrefix)
+}
+
+func ApiKeyDisplayPrefix(key string) string {
+	if len(key) < apiKeyDisplayLength {
+		return key
+	}
+	return key[:apiKeyDisplayLength]
+}
@@ -0,0 +1,208 @@
+package libs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+)
+
+// MaxBarcodeLength is the longest barcode the participants table can store.
+const MaxBarcodeLength = 14
+
+// BarcodeFormat describes the student card barcode. The student ID is laid
+// out as
+//
+//	YY C FF ...
+//
+// where YY is the last two digits of the entry year counted from
+// EntryYearBase, C the campus and FF the faculty. The ID may be followed by a
+// Luhn check digit.
+//
+// AllowUnparsed lets scans of barcodes that do not follow the format through
+// with empty student fields, for sites whose cards are not all known yet.
+type BarcodeFormat struct {
+	StudentIdLength int
+	CheckDigit      bool
+	EntryYearBase   int32
+	Campuses        map[byte]string
+	AllowUnparsed   bool
+}
+
+// DefaultBarcodeFormat is the Kasetsart student card: an 11 digit barcode made
+// of the 10 digit student ID and a Luhn check digit, with the entry year in
+// the Buddhist calendar.
+var DefaultBarcodeFormat = BarcodeFormat{
+	StudentIdLength: 10,
+	CheckDigit:      true,
+	EntryYearBase:   2500,
+	Campuses: map[byte]string{
+		'1': "Bang Khen",
+		'2': "Kamphaeng Saen",
+		'3': "Si Racha",
+		'4': "Chalermphrakiat Sakon Nakhon",
+	},
+}
+
+var barcodeFormat = DefaultBarcodeFormat
+
+// UseBarcodeFormat sets the format ParseStudentBarcode expects.
+func UseBarcodeFormat(format BarcodeFormat) {
+	barcodeFormat = format
+}
+
+// parseCampuses reads campuses written as "1=Bang Khen,2=Kamphaeng Saen".
+func parseCampuses(value string) (map[byte]string, error) {
+	campuses := map[byte]string{}
+	for _, entry := range strings.Split(value, ",") {
+		code, name, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || len(code) != 1 || name == "" {
+			return nil, fmt.Errorf("invalid campus %q", entry)
+		}
+		campuses[code[0]] = name
+	}
+
+	return campuses, nil
+}
+
+// BarcodeFormatFromEnv starts from DefaultBarcodeFormat and overrides it with
+// STUDENT_ID_LENGTH, BARCODE_CHECK_DIGIT, BARCODE_ENTRY_YEAR_BASE,
+// BARCODE_CAMPUSES and BARCODE_ALLOW_UNPARSED when they are set.
+func BarcodeFormatFromEnv() (BarcodeFormat, error) {
+	format := DefaultBarcodeFormat
+
+	if value := os.Getenv("STUDENT_ID_LENGTH"); value != "" {
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 5 || length > 10 {
+			return format, fmt.Errorf("invalid STUDENT_ID_LENGTH %q", value)
+		}
+		format.StudentIdLength = length
+	}
+
+	if value := os.Getenv("BARCODE_CHECK_DIGIT"); value != "" {
+		checkDigit, err := strconv.ParseBool(value)
+		if err != nil {
+			return format, fmt.Errorf("invalid BARCODE_CHECK_DIGIT %q", value)
+		}
+		format.CheckDigit = checkDigit
+	}
+
+	if value := os.Getenv("BARCODE_ENTRY_YEAR_BASE"); value != "" {
+		base, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return format, fmt.Errorf("invalid BARCODE_ENTRY_YEAR_BASE %q", value)
+		}
+		format.EntryYearBase = int32(base)
+	}
+
+	if value := os.Getenv("BARCODE_CAMPUSES"); value != "" {
+		campuses, err := parseCampuses(value)
+		if err != nil {
+			return format, err
+		}
+		format.Campuses = campuses
+	}
+
+	if value := os.Getenv("BARCODE_ALLOW_UNPARSED"); value != "" {
+		allowUnparsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return format, fmt.Errorf("invalid BARCODE_ALLOW_UNPARSED %q", value)
+		}
+		format.AllowUnparsed = allowUnparsed
+	}
+
+	return format, nil
+}
+
+func luhnCheckDigit(digits string) byte {
+	sum := 0
+	double := true
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse reads the student fields from barcode, ErrInvalidBarcode means the
+// barcode does not follow the format.
+func (f BarcodeFormat) Parse(barcode string) (*entities.StudentBarcode, error) {
+	length := f.StudentIdLength
+	if f.CheckDigit {
+		length++
+	}
+
+	if len(barcode) != length || !isDigits(barcode) {
+		return nil, nerrors.ErrInvalidBarcode
+	}
+
+	studentId := barcode[:f.StudentIdLength]
+
+	if f.CheckDigit && luhnCheckDigit(studentId) != barcode[f.StudentIdLength] {
+		return nil, nerrors.ErrInvalidBarcode
+	}
+
+	if _, ok := f.Campuses[studentId[2]]; !ok {
+		return nil, nerrors.ErrInvalidBarcode
+	}
+
+	year, err := strconv.Atoi(studentId[:2])
+	if err != nil {
+		return nil, nerrors.ErrInvalidBarcode
+	}
+
+	return &entities.StudentBarcode{
+		StudentId: studentId,
+		Campus:    studentId[2:3],
+		Faculty:   studentId[3:5],
+		EntryYear: f.EntryYearBase + int32(year),
+	}, nil
+}
+
+// Read parses the barcode of a scan. With AllowUnparsed a barcode that does
+// not follow the format is read with empty student fields, as long as it fits
+// in MaxBarcodeLength.
+func (f BarcodeFormat) Read(barcode string) (entities.StudentBarcode, error) {
+	student, err := f.Parse(barcode)
+	if err == nil {
+		return *student, nil
+	}
+
+	if !f.AllowUnparsed || barcode == "" || len(barcode) > MaxBarcodeLength {
+		return entities.StudentBarcode{}, err
+	}
+
+	return entities.StudentBarcode{}, nil
+}
+
+// ParseStudentBarcode parses barcode with the format set by UseBarcodeFormat.
+func ParseStudentBarcode(barcode string) (*entities.StudentBarcode, error) {
+	return barcodeFormat.Parse(barcode)
+}
+
+// ReadStudentBarcode reads the barcode of a scan with the format set by
+// UseBarcodeFormat.
+func ReadStudentBarcode(barcode string) (entities.StudentBarcode, error) {
+	return barcodeFormat.Read(barcode)
+}
@@ -0,0 +1,149 @@
+package libs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+)
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"7992739871", '3'},
+		{"6410500001", '8'},
+		{"6520301234", '2'},
+		{"0000000000", '0'},
+		{"654050012", '8'},
+	}
+
+	for _, tt := range tests {
+		if got := luhnCheckDigit(tt.digits); got != tt.want {
+			t.Errorf("luhnCheckDigit(%q) = %q, want %q", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestBarcodeFormatParse(t *testing.T) {
+	noCheckDigit := DefaultBarcodeFormat
+	noCheckDigit.CheckDigit = false
+
+	otherCampuses := DefaultBarcodeFormat
+	otherCampuses.Campuses = map[byte]string{'5': "Suphan Buri"}
+	otherCampuses.EntryYearBase = 2000
+
+	tests := []struct {
+		name    string
+		format  BarcodeFormat
+		barcode string
+		want    *entities.StudentBarcode
+	}{
+		{
+			name:    "valid",
+			format:  DefaultBarcodeFormat,
+			barcode: "64105000018",
+			want:    &entities.StudentBarcode{StudentId: "6410500001", Campus: "1", Faculty: "05", EntryYear: 2564},
+		},
+		{
+			name:    "other campus",
+			format:  DefaultBarcodeFormat,
+			barcode: "65203012342",
+			want:    &entities.StudentBarcode{StudentId: "6520301234", Campus: "2", Faculty: "03", EntryYear: 2565},
+		},
+		{name: "wrong check digit", format: DefaultBarcodeFormat, barcode: "64105000019"},
+		{name: "too short", format: DefaultBarcodeFormat, barcode: "6410500001"},
+		{name: "too long", format: DefaultBarcodeFormat, barcode: "641050000180"},
+		{name: "not digits", format: DefaultBarcodeFormat, barcode: "64105A00018"},
+		{name: "unknown campus", format: DefaultBarcodeFormat, barcode: "66500000006"},
+		{name: "empty", format: DefaultBarcodeFormat, barcode: ""},
+		{
+			name:    "without check digit",
+			format:  noCheckDigit,
+			barcode: "6410500001",
+			want:    &entities.StudentBarcode{StudentId: "6410500001", Campus: "1", Faculty: "05", EntryYear: 2564},
+		},
+		{name: "check digit not expected", format: noCheckDigit, barcode: "64105000018"},
+		{
+			name:    "configured campus and year",
+			format:  otherCampuses,
+			barcode: "24501234561",
+			want:    &entities.StudentBarcode{StudentId: "2450123456", Campus: "5", Faculty: "01", EntryYear: 2024},
+		},
+		{name: "campus not configured", format: otherCampuses, barcode: "64105000018"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.format.Parse(tt.barcode)
+
+			if tt.want == nil {
+				if !errors.Is(err, nerrors.ErrInvalidBarcode) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidBarcode", tt.barcode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.barcode, err)
+			}
+
+			if *got != *tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.barcode, *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestBarcodeFormatFromEnv(t *testing.T) {
+	t.Setenv("STUDENT_ID_LENGTH", "8")
+	t.Setenv("BARCODE_CHECK_DIGIT", "false")
+	t.Setenv("BARCODE_ENTRY_YEAR_BASE", "2000")
+	t.Setenv("BARCODE_CAMPUSES", "1=Main, 2=North")
+	t.Setenv("BARCODE_ALLOW_UNPARSED", "true")
+
+	format, err := BarcodeFormatFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if format.StudentIdLength != 8 || format.CheckDigit || format.EntryYearBase != 2000 || !format.AllowUnparsed {
+		t.Errorf("BarcodeFormatFromEnv() = %+v", format)
+	}
+
+	if format.Campuses['1'] != "Main" || format.Campuses['2'] != "North" || len(format.Campuses) != 2 {
+		t.Errorf("Campuses = %v", format.Campuses)
+	}
+
+	t.Setenv("BARCODE_CAMPUSES", "Main")
+	if _, err := BarcodeFormatFromEnv(); err == nil {
+		t.Error("BarcodeFormatFromEnv() accepted an invalid campus")
+	}
+}
+
+func TestBarcodeFormatRead(t *testing.T) {
+	if _, err := DefaultBarcodeFormat.Read("12345"); !errors.Is(err, nerrors.ErrInvalidBarcode) {
+		t.Errorf("Read() error = %v, want ErrInvalidBarcode", err)
+	}
+
+	lenient := DefaultBarcodeFormat
+	lenient.AllowUnparsed = true
+
+	student, err := lenient.Read("12345")
+	if err != nil || student != (entities.StudentBarcode{}) {
+		t.Errorf("lenient Read() = %+v, %v, want empty student fields", student, err)
+	}
+
+	student, err = lenient.Read("64105000018")
+	if err != nil || student.StudentId != "6410500001" {
+		t.Errorf("lenient Read() = %+v, %v, want parsed student fields", student, err)
+	}
+
+	for _, barcode := range []string{"", "123456789012345"} {
+		if _, err := lenient.Read(barcode); !errors.Is(err, nerrors.ErrInvalidBarcode) {
+			t.Errorf("lenient Read(%q) error = %v, want ErrInvalidBarcode", barcode, err)
+		}
+	}
+}
@@ -1,53 +1,119 @@
 package libs
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func GenerateAccessToken(email string, name string, picture string, role string) (*string, *time.Time, error) {
-	accessExp := time.Now().Add(time.Hour * 1)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+const (
+	AccessTokenLifetime  = time.Hour * 1
+	RefreshTokenLifetime = time.Hour * 24 * 10
+)
+
+// Both token types are signed with the same key, so the typ claim keeps a
+// refresh token from being accepted as an access token and the other way
+// round.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenAudience    = "nisit-scan-api"
+)
+
+// signToken signs claims with the current key of Keys, naming it in the kid
+// header so verifiers can pick the matching key from the JWKS.
+func signToken(tokenType string, claims jwt.MapClaims) (*string, error) {
+	kid, key, err := Keys.current()
+	if err != nil {
+		return nil, err
+	}
+
+	claims["typ"] = tokenType
+	claims["aud"] = TokenAudience
+
+	if issuer := os.Getenv("API_URL"); issuer != "" {
+		claims["iss"] = issuer
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		return nil, nerrors.ErrSomethingWentWrong
+	}
+
+	return &signedToken, nil
+}
+
+func GenerateAccessToken(sessionId string, version int32, email string, name string, picture string, role string) (*string, *time.Time, error) {
+	accessExp := time.Now().Add(AccessTokenLifetime)
+	signedToken, err := signToken(TokenTypeAccess, jwt.MapClaims{
+		"sid":     sessionId,
+		"ver":     version,
 		"email":   email,
 		"name":    name,
 		"picture": picture,
 		"role":    role,
 		"exp":     accessExp.Unix(),
 	})
-
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
-		return nil, nil, nerrors.ErrSomethingWentWrong
+		return nil, nil, err
 	}
 
-	return &signedToken, &accessExp, nil
+	return signedToken, &accessExp, nil
 }
 
-func GenerateRefreshToken(email string) (*string, *time.Time, error) {
-	refreshExp := time.Now().Add(time.Hour * 24 * 10)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func GenerateRefreshToken(sessionId string, email string) (*string, *time.Time, error) {
+	refreshExp := time.Now().Add(RefreshTokenLifetime)
+	signedToken, err := signToken(TokenTypeRefresh, jwt.MapClaims{
+		"jti":   uuid.NewString(),
+		"sid":   sessionId,
 		"email": email,
 		"exp":   refreshExp.Unix(),
 	})
-
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
-		return nil, nil, nerrors.ErrSomethingWentWrong
+		return nil, nil, err
 	}
 
-	return &signedToken, &refreshExp, nil
+	return signedToken, &refreshExp, nil
+}
+
+// HashToken returns the hex SHA-256 of token for storage.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func ParseJwt(tok string) (map[string]interface{}, error) {
+// ParseJwt verifies tok against the key named by its kid header and checks
+// that it is a tokenType token issued for TokenAudience. Access tokens
+// signed with JWT_SECRET before the switch to RS256 are still accepted while
+// the variable is set, so existing sessions survive the upgrade.
+func ParseJwt(tok string, tokenType string) (map[string]interface{}, error) {
 
 	claims := jwt.MapClaims{}
 
 	token, err := jwt.ParseWithClaims(tok, &claims, func(t *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+		if t.Method == jwt.SigningMethodHS256 {
+			if tokenType != TokenTypeAccess {
+				return nil, nerrors.ErrTokenNotValid
+			}
+			secret := os.Getenv("JWT_SECRET")
+			if secret == "" {
+				return nil, nerrors.ErrTokenNotValid
+			}
+			return []byte(secret), nil
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		return Keys.publicKey(kid)
+	}, jwt.WithValidMethods([]string{SigningAlgorithm, jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
 		return nil, err
 	}
@@ -56,5 +122,19 @@ func ParseJwt(tok string) (map[string]interface{}, error) {
 		return nil, nerrors.ErrTokenNotValid
 	}
 
+	// Legacy tokens predate the typ and aud claims
+	if token.Method == jwt.SigningMethodHS256 {
+		return claims, nil
+	}
+
+	if typ, _ := claims["typ"].(string); typ != tokenType {
+		return nil, nerrors.ErrTokenNotValid
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil || !slices.Contains(audience, TokenAudience) {
+		return nil, nerrors.ErrTokenNotValid
+	}
+
 	return claims, nil
 }
@@ -0,0 +1,212 @@
+package libs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/go-jose/go-jose/v4"
+)
+
+const SigningAlgorithm = "RS256"
+
+// reloadInterval limits how often an unknown kid triggers a reload, so
+// tokens with made up kids cannot hammer the database.
+const reloadInterval = 30 * time.Second
+
+// KeyPublishAhead is how long a new key is published in the JWKS before it
+// starts signing. It matches the JWKS cache lifetime, so verifiers holding a
+// cached copy already know the key when the first token signed with it
+// arrives.
+const KeyPublishAhead = 5 * time.Minute
+
+type signer struct {
+	kid        string
+	key        *rsa.PrivateKey
+	activeFrom time.Time
+}
+
+type keySetImpl struct {
+	mu         sync.RWMutex
+	signers    []signer
+	publicKeys map[string]*rsa.PublicKey
+	jwks       jose.JSONWebKeySet
+	reload     func() error
+	reloadedAt time.Time
+}
+
+var Keys = NewKeySet()
+
+func NewKeySet() *keySetImpl {
+	return &keySetImpl{
+		publicKeys: map[string]*rsa.PublicKey{},
+	}
+}
+
+// GenerateSigningKey creates a new RSA key. The kid is the RFC 7638
+// thumbprint of the public key.
+func GenerateSigningKey() (*entities.SigningKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbprint, err := (&jose.JSONWebKey{Key: &privateKey.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.SigningKey{
+		Kid:        base64.RawURLEncoding.EncodeToString(thumbprint),
+		Algorithm:  SigningAlgorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil
+}
+
+func parsePrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not rsa")
+	}
+
+	return privateKey, nil
+}
+
+// Load replaces the key set. keys must be ordered by ActiveFrom, latest
+// first. The latest key that is already active signs new tokens and all of
+// them verify.
+func (k *keySetImpl) Load(keys []entities.SigningKey) error {
+	if len(keys) == 0 {
+		return errors.New("no signing keys")
+	}
+
+	var signers []signer
+	publicKeys := map[string]*rsa.PublicKey{}
+	jwks := jose.JSONWebKeySet{}
+
+	for _, key := range keys {
+		privateKey, err := parsePrivateKey(key.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		signers = append(signers, signer{
+			kid:        key.Kid,
+			key:        privateKey,
+			activeFrom: key.ActiveFrom,
+		})
+
+		publicKeys[key.Kid] = &privateKey.PublicKey
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{
+			Key:       &privateKey.PublicKey,
+			KeyID:     key.Kid,
+			Algorithm: key.Algorithm,
+			Use:       "sig",
+		})
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.signers = signers
+	k.publicKeys = publicKeys
+	k.jwks = jwks
+	k.reloadedAt = time.Now()
+
+	return nil
+}
+
+// SetReload registers how to fetch the keys again when a token names a kid
+// this instance has not seen, e.g. one just created by another instance.
+func (k *keySetImpl) SetReload(reload func() error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.reload = reload
+}
+
+// current returns the key to sign with. A key still waiting out
+// KeyPublishAhead is skipped unless no other key is loaded.
+func (k *keySetImpl) current() (string, *rsa.PrivateKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if len(k.signers) == 0 {
+		return "", nil, nerrors.ErrSomethingWentWrong
+	}
+
+	now := time.Now()
+	for _, s := range k.signers {
+		if !s.activeFrom.After(now) {
+			return s.kid, s.key, nil
+		}
+	}
+
+	oldest := k.signers[len(k.signers)-1]
+	return oldest.kid, oldest.key, nil
+}
+
+func (k *keySetImpl) lookup(kid string) (*rsa.PublicKey, bool, func() error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.publicKeys[kid]
+	if ok || k.reload == nil || time.Since(k.reloadedAt) < reloadInterval {
+		return key, ok, nil
+	}
+
+	return nil, false, k.reload
+}
+
+func (k *keySetImpl) publicKey(kid string) (*rsa.PublicKey, error) {
+	key, ok, reload := k.lookup(kid)
+	if ok {
+		return key, nil
+	}
+
+	if reload == nil {
+		return nil, nerrors.ErrTokenNotValid
+	}
+
+	if err := reload(); err != nil {
+		return nil, err
+	}
+
+	key, ok, _ = k.lookup(kid)
+	if !ok {
+		return nil, nerrors.ErrTokenNotValid
+	}
+
+	return key, nil
+}
+
+// JWKS returns the public half of every active key.
+func (k *keySetImpl) JWKS() jose.JSONWebKeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.jwks
+}
@@ -0,0 +1,80 @@
+package libs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+)
+
+func newTestKeys(t *testing.T, activeFrom ...time.Time) []entities.SigningKey {
+	t.Helper()
+
+	var keys []entities.SigningKey
+	for _, at := range activeFrom {
+		key, err := GenerateSigningKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		key.ActiveFrom = at
+		keys = append(keys, *key)
+	}
+
+	return keys
+}
+
+func TestPendingKeyIsPublishedButDoesNotSign(t *testing.T) {
+	now := time.Now()
+	keys := newTestKeys(t, now.Add(KeyPublishAhead), now.Add(-time.Hour))
+
+	set := NewKeySet()
+	if err := set.Load(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _, err := set.current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kid != keys[1].Kid {
+		t.Errorf("signing kid = %s, want the active key %s", kid, keys[1].Kid)
+	}
+
+	jwks := set.JWKS()
+	if len(jwks.Key(keys[0].Kid)) == 0 {
+		t.Error("pending key is missing from the JWKS")
+	}
+}
+
+func TestParseJwtChecksTokenType(t *testing.T) {
+	if err := Keys.Load(newTestKeys(t, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, _, err := GenerateAccessToken("sid", 0, "user@example.com", "User", "", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshToken, _, err := GenerateRefreshToken("sid", "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseJwt(*accessToken, TokenTypeAccess); err != nil {
+		t.Errorf("access token as access: %v", err)
+	}
+
+	if _, err := ParseJwt(*refreshToken, TokenTypeRefresh); err != nil {
+		t.Errorf("refresh token as refresh: %v", err)
+	}
+
+	if _, err := ParseJwt(*refreshToken, TokenTypeAccess); err == nil {
+		t.Error("refresh token was accepted as an access token")
+	}
+
+	if _, err := ParseJwt(*accessToken, TokenTypeRefresh); err == nil {
+		t.Error("access token was accepted as a refresh token")
+	}
+}
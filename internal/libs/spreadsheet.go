@@ -0,0 +1,115 @@
+package libs
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/xuri/excelize/v2"
+)
+
+// ReadSpreadsheet reads every row of a CSV file or the first sheet of an
+// XLSX workbook, picking the format from the file extension.
+func ReadSpreadsheet(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return f.GetRows(f.GetSheetName(0))
+	}
+
+	return nil, nerrors.ErrUnsupportedFileFormat
+}
+
+type SpreadsheetWriter interface {
+	WriteRow(row []string) error
+	Flush() error
+	Close() error
+}
+
+// NewSpreadsheetWriter writes rows as CSV or as the first sheet of an XLSX
+// workbook. CSV rows reach w on every Flush, while an XLSX workbook is kept
+// in excelize's temporary files and only written out on Close.
+func NewSpreadsheetWriter(format string, w io.Writer) (SpreadsheetWriter, error) {
+	switch format {
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "xlsx":
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(f.GetSheetName(0))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		return &xlsxWriter{f: f, sw: sw, w: w}, nil
+	}
+
+	return nil, nerrors.ErrUnsupportedFileFormat
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteRow(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
+
+type xlsxWriter struct {
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	w   io.Writer
+	row int
+}
+
+func (x *xlsxWriter) WriteRow(row []string) error {
+	x.row++
+
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(row))
+	for i, value := range row {
+		values[i] = value
+	}
+
+	return x.sw.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Flush() error {
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+
+	err := x.sw.Flush()
+	if err != nil {
+		return err
+	}
+
+	return x.f.Write(x.w)
+}
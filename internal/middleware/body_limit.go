@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errBodyTooLarge is returned reading a chunked body past the limit.
+var errBodyTooLarge = errors.New("request body is too large")
+
+// limitedReader fails with errBodyTooLarge once more than n bytes are read,
+// where io.LimitReader would end the body early as if it were complete.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+
+	// Read one byte past the limit to tell a body of exactly n bytes apart
+	// from a longer one
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errBodyTooLarge
+	}
+
+	return n, err
+}
+
+// isImport reports whether the request uploads a spreadsheet to one of the
+// import routes.
+func isImport(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPost && strings.HasSuffix(c.Path(), "/import")
+}
+
+func tooLarge(c *fiber.Ctx) error {
+	// The body is left unread, so the connection cannot be reused
+	c.Request().SetConnectionClose()
+	c.Response().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"code":    "REQUEST_TOO_LARGE",
+		"message": "Request body is too large",
+	})
+}
+
+// NewBodyLimit caps request bodies at limit bytes, or importLimit for the
+// import routes. The server streams request bodies, so without this any route
+// would read an upload of any size.
+func NewBodyLimit(limit int, importLimit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		max := limit
+		if isImport(c) {
+			max = importLimit
+		}
+
+		length := c.Request().Header.ContentLength()
+		if length > max {
+			return tooLarge(c)
+		}
+
+		// A chunked body has no length up front. It is read here up to the
+		// cap, the stream cannot be wrapped in place because replacing it
+		// releases the connection reader underneath.
+		if stream := c.Request().BodyStream(); length < 0 && stream != nil {
+			body, err := io.ReadAll(&limitedReader{r: stream, n: int64(max)})
+			if errors.Is(err, errBodyTooLarge) {
+				return tooLarge(c)
+			}
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"code":    "INVALID_REQUEST",
+					"message": "Cannot read request body",
+				})
+			}
+
+			c.Request().SetBody(body)
+			c.Request().Header.SetContentLength(len(body))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newBodyLimitApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{StreamRequestBody: true})
+	app.Use(NewBodyLimit(100, 1000))
+	app.Post("/students/import", func(c *fiber.Ctx) error {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+
+		return c.Send(content)
+	})
+
+	return app
+}
+
+// chunkedUpload builds a multipart upload of size bytes sent without a
+// Content-Length, as a client streaming the file would.
+func chunkedUpload(t *testing.T, size int) (*http.Request, []byte) {
+	t.Helper()
+
+	content := bytes.Repeat([]byte("a"), size)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "students.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/students/import", io.MultiReader(&body))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.ContentLength = -1
+	req.TransferEncoding = []string{"chunked"}
+
+	return req, content
+}
+
+func TestBodyLimitRejectsChunkedUploadOverLimit(t *testing.T) {
+	app := newBodyLimitApp(t)
+	req, _ := chunkedUpload(t, 5000)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+}
+
+func TestBodyLimitKeepsChunkedUploadUnderLimit(t *testing.T) {
+	app := newBodyLimitApp(t)
+	req, content := chunkedUpload(t, 500)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Errorf("upload was read as %d bytes, want %d", len(got), len(content))
+	}
+}
@@ -1,19 +1,43 @@
 package middleware
 
 import (
+	"log"
+	"strings"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
 	"github.com/SornchaiTheDev/nisit-scan-backend/internal/libs"
 	"github.com/gofiber/fiber/v2"
 )
 
 type AccessToken struct {
-	Email string
-	Name  string
-	Role  string
+	SessionId string
+	Email     string
+	Name      string
+	Role      string
 }
 
+var tokenService services.TokenService
+
+// UseTokenService lets Jwt reject access tokens revoked through
+// TokenService.RevokeAccessTokens. Call it once at startup.
+func UseTokenService(s services.TokenService) {
+	tokenService = s
+}
+
+// BearerToken returns the token sent as "Authorization: Bearer <token>".
+func BearerToken(c *fiber.Ctx) (string, bool) {
+	return strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+}
+
+// Jwt accepts the access token from the Authorization header, used by native
+// apps, or from the accessToken cookie set for the web app.
 func Jwt(c *fiber.Ctx) error {
 
-	accessToken := c.Cookies("accessToken")
+	accessToken, ok := BearerToken(c)
+	if !ok {
+		accessToken = c.Cookies("accessToken")
+	}
+
 	if accessToken == "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"code":    "UNAUTHORIZED",
@@ -21,7 +45,7 @@ func Jwt(c *fiber.Ctx) error {
 		})
 	}
 
-	claims, err := libs.ParseJwt(accessToken)
+	claims, err := libs.ParseJwt(accessToken, libs.TokenTypeAccess)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"code":    "UNAUTHORIZED",
@@ -29,10 +53,42 @@ func Jwt(c *fiber.Ctx) error {
 		})
 	}
 
+	email, emailOk := claims["email"].(string)
+	role, roleOk := claims["role"].(string)
+	if !emailOk || !roleOk {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":    "UNAUTHORIZED",
+			"message": "Unauthorized",
+		})
+	}
+
+	if tokenService != nil {
+		currentVersion, err := tokenService.GetTokenVersion(email)
+		if err != nil {
+			log.Println("Cannot get token version: ", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":    "SOMETHING_WENT_WRONG",
+				"message": "Something went wrong",
+			})
+		}
+
+		version, _ := claims["ver"].(float64)
+		if int32(version) < currentVersion {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "TOKEN_REVOKED",
+				"message": "Token has been revoked",
+			})
+		}
+	}
+
+	sessionId, _ := claims["sid"].(string)
+	name, _ := claims["name"].(string)
+
 	c.Locals("token", AccessToken{
-		Email: claims["email"].(string),
-		Name:  claims["name"].(string),
-		Role:  claims["role"].(string),
+		SessionId: sessionId,
+		Email:     email,
+		Name:      name,
+		Role:      role,
 	})
 
 	return c.Next()
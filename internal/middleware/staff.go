@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/services"
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,33 +15,35 @@ func NewStaffMiddleware(staffService services.StaffService) *staffMiddleware {
 		staffService: staffService,
 	}
 }
-func (m *staffMiddleware) Staff(c *fiber.Ctx) error {
-	claims, ok := c.Locals("token").(AccessToken)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"code":    "UNAUTHORIZED",
-			"message": "Unauthorized",
-		})
-	}
-	eventId := c.Params("id")
-
-	isAuthorized := true
-
-	staff, err := m.staffService.GetByEmailAndEventId(claims.Email, eventId)
-	if err != nil || staff == nil {
-		isAuthorized = false
-	}
 
-	if claims.Role == "admin" {
-		isAuthorized = true
-	}
+// Staff allows admins and any staff member of the event.
+func (m *staffMiddleware) Staff(c *fiber.Ctx) error {
+	return m.Role(entities.StaffScanner)(c)
+}
 
-	if !isAuthorized {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"code":    "UNAUTHORIZED",
-			"message": "Unauthorized",
-		})
+// Role allows admins and staff members of the event whose role includes role.
+func (m *staffMiddleware) Role(role entities.StaffRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("token").(AccessToken)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "UNAUTHORIZED",
+				"message": "Unauthorized",
+			})
+		}
+
+		if claims.Role == "admin" {
+			return c.Next()
+		}
+
+		isAuthorized, err := m.staffService.HasRole(claims.Email, c.Params("id"), role)
+		if err != nil || !isAuthorized {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":    "UNAUTHORIZED",
+				"message": "Unauthorized",
+			})
+		}
+
+		return c.Next()
 	}
-
-	return c.Next()
 }
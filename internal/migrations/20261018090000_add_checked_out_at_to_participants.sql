@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS checked_out_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE participants DROP COLUMN IF EXISTS checked_out_at;
+-- +goose StatementEnd
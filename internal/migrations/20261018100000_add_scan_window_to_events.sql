@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+ADD COLUMN IF NOT EXISTS start_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS end_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS grace_before_minutes INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS grace_after_minutes INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events
+DROP COLUMN IF EXISTS start_at,
+DROP COLUMN IF EXISTS end_at,
+DROP COLUMN IF EXISTS grace_before_minutes,
+DROP COLUMN IF EXISTS grace_after_minutes;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE event_sessions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	event_id UUID NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	start_at TIMESTAMP NOT NULL,
+	end_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE session_participants (
+	barcode VARCHAR(14) NOT NULL,
+	timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	session_id UUID NOT NULL,
+
+	PRIMARY KEY(barcode,session_id),
+	FOREIGN KEY(session_id) REFERENCES event_sessions(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE session_participants;
+DROP TABLE event_sessions;
+-- +goose StatementEnd
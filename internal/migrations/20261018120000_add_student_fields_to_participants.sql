@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE participants
+ADD COLUMN IF NOT EXISTS student_id VARCHAR(10),
+ADD COLUMN IF NOT EXISTS campus VARCHAR(1),
+ADD COLUMN IF NOT EXISTS faculty VARCHAR(2),
+ADD COLUMN IF NOT EXISTS entry_year INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE participants
+DROP COLUMN IF EXISTS student_id,
+DROP COLUMN IF EXISTS campus,
+DROP COLUMN IF EXISTS faculty,
+DROP COLUMN IF EXISTS entry_year;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE students (
+	student_id VARCHAR(10) PRIMARY KEY,
+	full_name VARCHAR(255) NOT NULL,
+	faculty VARCHAR(255) NOT NULL,
+	major VARCHAR(255) NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE students;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS scan_id UUID UNIQUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE participants DROP COLUMN IF EXISTS scan_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS scanned_by VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE participants DROP COLUMN IF EXISTS scanned_by;
+-- +goose StatementEnd
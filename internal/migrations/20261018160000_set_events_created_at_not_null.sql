@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE events SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+
+ALTER TABLE events ALTER COLUMN created_at SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events ALTER COLUMN created_at DROP NOT NULL;
+-- +goose StatementEnd
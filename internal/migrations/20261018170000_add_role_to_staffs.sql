@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE staffs ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'supervisor'
+	CHECK (role IN ('scanner', 'supervisor', 'co_organiser'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE staffs DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_logs (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	actor_email VARCHAR(255) NOT NULL,
+	actor_role VARCHAR(20) NOT NULL,
+	action VARCHAR(100) NOT NULL,
+	target_type VARCHAR(50) NOT NULL,
+	target_id VARCHAR(255) NOT NULL,
+	before JSONB,
+	after JSONB,
+	ip VARCHAR(45) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_logs_created_at_idx ON audit_logs (created_at);
+CREATE INDEX audit_logs_target_idx ON audit_logs (target_type, target_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_logs;
+-- +goose StatementEnd
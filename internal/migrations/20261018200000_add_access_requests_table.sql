@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE access_requests (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	email VARCHAR(255) NOT NULL,
+	full_name VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+	reviewed_by VARCHAR(255),
+	reviewed_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX access_requests_pending_email_idx ON access_requests (email) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE access_requests;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE refresh_tokens;
+
+CREATE TABLE refresh_tokens (
+	id UUID PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	token TEXT NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip VARCHAR(45) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refresh_tokens_email_idx ON refresh_tokens (email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE refresh_tokens;
+
+CREATE TABLE refresh_tokens (
+	email VARCHAR(255) PRIMARY KEY,
+	token VARCHAR(255) NOT NULL
+);
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens RENAME COLUMN token TO token_hash;
+UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash TYPE VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM refresh_tokens;
+ALTER TABLE refresh_tokens ALTER COLUMN token_hash TYPE TEXT;
+ALTER TABLE refresh_tokens RENAME COLUMN token_hash TO token;
+-- +goose StatementEnd
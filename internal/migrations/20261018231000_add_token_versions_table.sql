@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE token_versions (
+	email VARCHAR(255) PRIMARY KEY,
+	version INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE token_versions;
+-- +goose StatementEnd
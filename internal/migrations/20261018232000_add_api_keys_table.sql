@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	event_id UUID NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	prefix VARCHAR(12) NOT NULL,
+	key_hash VARCHAR(64) NOT NULL UNIQUE,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP,
+	last_used_at TIMESTAMP,
+
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX api_keys_event_id_idx ON api_keys (event_id);
+
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE participants DROP COLUMN IF EXISTS api_key_id;
+DROP TABLE api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing events were scannable, keep them open
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('draft', 'published', 'open', 'closed', 'archived'));
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'draft';
+CREATE INDEX events_status_idx ON events (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS events_status_idx;
+ALTER TABLE events DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ADD COLUMN IF NOT EXISTS registration_mode VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (registration_mode IN ('open', 'registered_only', 'warn_unregistered'));
+
+CREATE TABLE registrations (
+	event_id UUID NOT NULL,
+	student_id VARCHAR(10) NOT NULL,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY(event_id, student_id),
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE registrations;
+ALTER TABLE events DROP COLUMN IF EXISTS registration_mode;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE signing_keys ADD COLUMN IF NOT EXISTS active_from TIMESTAMP;
+UPDATE signing_keys SET active_from = created_at WHERE active_from IS NULL;
+ALTER TABLE signing_keys ALTER COLUMN active_from SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE signing_keys ALTER COLUMN active_from SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE signing_keys DROP COLUMN IF EXISTS active_from;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE oauth_states (
+	state VARCHAR(64) PRIMARY KEY,
+	provider VARCHAR(64) NOT NULL,
+	binding VARCHAR(64) NOT NULL,
+	verifier VARCHAR(128) NOT NULL,
+	nonce VARCHAR(64) NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE exchange_codes (
+	code_hash VARCHAR(64) PRIMARY KEY,
+	code_challenge VARCHAR(128) NOT NULL,
+	session_id VARCHAR(64) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	picture TEXT NOT NULL,
+	role VARCHAR(20) NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE http_sessions (
+	id VARCHAR(64) PRIMARY KEY,
+	data BYTEA NOT NULL,
+	expires_at TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE http_sessions;
+DROP TABLE exchange_codes;
+DROP TABLE oauth_states;
+-- +goose StatementEnd
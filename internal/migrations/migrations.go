@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+)
+
+//go:embed *.sql
+var migrations embed.FS
+
+// Run executes a goose command such as up, down, status or version against
+// the migrations embedded in the binary.
+func Run(ctx context.Context, pool *pgxpool.Pool, command string, args ...string) error {
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+
+	goose.SetBaseFS(migrations)
+
+	err := goose.SetDialect("postgres")
+	if err != nil {
+		return err
+	}
+
+	return goose.RunContext(ctx, command, db, ".", args...)
+}
@@ -0,0 +1,68 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+)
+
+const subscriberBufferSize = 64
+
+type memoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+// NewMemoryPubSub only reaches subscribers in the same process, so it suits a
+// single server instance.
+func NewMemoryPubSub() repositories.PubSub {
+	return newMemoryPubSub()
+}
+
+func newMemoryPubSub() *memoryPubSub {
+	return &memoryPubSub{
+		subscribers: map[string]map[chan []byte]struct{}{},
+	}
+}
+
+// Publish never blocks: a subscriber whose buffer is full misses the message.
+func (m *memoryPubSub) Publish(topic string, message []byte) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for ch := range m.subscribers[topic] {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryPubSub) Subscribe(topic string) (<-chan []byte, func(), error) {
+	ch := make(chan []byte, subscriberBufferSize)
+
+	m.mu.Lock()
+	if m.subscribers[topic] == nil {
+		m.subscribers[topic] = map[chan []byte]struct{}{}
+	}
+	m.subscribers[topic][ch] = struct{}{}
+	m.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			delete(m.subscribers[topic], ch)
+			if len(m.subscribers[topic]) == 0 {
+				delete(m.subscribers, topic)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe, nil
+}
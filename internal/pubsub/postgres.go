@@ -0,0 +1,108 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const postgresChannel = "nisit_scan_feed"
+
+// maxNotifyPayload is the Postgres limit on a NOTIFY payload, payloads must
+// be shorter than it.
+const maxNotifyPayload = 8000
+
+type notification struct {
+	Topic   string `json:"topic"`
+	Message []byte `json:"message"`
+}
+
+type postgresPubSub struct {
+	ctx   context.Context
+	db    *pgxpool.Pool
+	local *memoryPubSub
+}
+
+// NewPostgresPubSub fans messages out through Postgres LISTEN/NOTIFY so every
+// server instance sharing the database receives them. Each instance holds one
+// listening connection and hands messages to its local subscribers.
+func NewPostgresPubSub(ctx context.Context, db *pgxpool.Pool) repositories.PubSub {
+	p := &postgresPubSub{
+		ctx:   ctx,
+		db:    db,
+		local: newMemoryPubSub(),
+	}
+
+	go p.listen()
+
+	return p
+}
+
+func (p *postgresPubSub) listen() {
+	for {
+		err := p.receive()
+		if p.ctx.Err() != nil {
+			return
+		}
+
+		log.Println("Pub/sub listener disconnected: ", err)
+		time.Sleep(time.Second)
+	}
+}
+
+func (p *postgresPubSub) receive() error {
+	pooled, err := p.db.Acquire(p.ctx)
+	if err != nil {
+		return err
+	}
+
+	// A listening connection must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(p.ctx, "LISTEN "+postgresChannel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.WaitForNotification(p.ctx)
+		if err != nil {
+			return err
+		}
+
+		var msg notification
+		err = json.Unmarshal([]byte(n.Payload), &msg)
+		if err != nil {
+			continue
+		}
+
+		p.local.Publish(msg.Topic, msg.Message)
+	}
+}
+
+func (p *postgresPubSub) Publish(topic string, message []byte) error {
+	payload, err := json.Marshal(notification{
+		Topic:   topic,
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) >= maxNotifyPayload {
+		return nerrors.ErrMessageTooLarge
+	}
+
+	_, err = p.db.Exec(p.ctx, "SELECT pg_notify($1, $2)", postgresChannel, string(payload))
+	return err
+}
+
+func (p *postgresPubSub) Subscribe(topic string) (<-chan []byte, func(), error) {
+	return p.local.Subscribe(topic)
+}
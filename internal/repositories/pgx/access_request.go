@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type accessRequestRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewAccessRequestRepo(ctx context.Context, q *sqlc.Queries) repositories.AccessRequestRepository {
+	return &accessRequestRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func parseAccessRequest(request sqlc.AccessRequest) entities.AccessRequest {
+	result := entities.AccessRequest{
+		Id:        request.ID,
+		Email:     request.Email,
+		FullName:  request.FullName,
+		Status:    entities.AccessRequestStatus(request.Status),
+		CreatedAt: request.CreatedAt.Time,
+	}
+
+	if request.ReviewedBy.Valid {
+		result.ReviewedBy = &request.ReviewedBy.String
+	}
+
+	if request.ReviewedAt.Valid {
+		result.ReviewedAt = &request.ReviewedAt.Time
+	}
+
+	return result
+}
+
+// Create opens a pending request for email. It is a no-op when the email
+// already has one pending.
+func (r *accessRequestRepo) Create(email string, fullName string) error {
+	return r.q.CreateAccessRequest(r.ctx, sqlc.CreateAccessRequestParams{
+		Email:    email,
+		FullName: fullName,
+	})
+}
+
+func (r *accessRequestRepo) GetById(id uuid.UUID) (*entities.AccessRequest, error) {
+	request, err := r.q.GetAccessRequestById(r.ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrAccessRequestNotFound
+		}
+		return nil, err
+	}
+
+	result := parseAccessRequest(request)
+
+	return &result, nil
+}
+
+func (r *accessRequestRepo) GetPagination(status string, search string, pageIndex int32, pageSize int32) ([]entities.AccessRequest, error) {
+	requests, err := r.q.GetAccessRequestPagination(r.ctx, sqlc.GetAccessRequestPaginationParams{
+		Status: exactOrAny(status),
+		Email:  fmt.Sprintf("%%%s%%", search),
+		Limit:  pageSize,
+		Offset: pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.AccessRequest
+	for _, request := range requests {
+		result = append(result, parseAccessRequest(request))
+	}
+
+	return result, nil
+}
+
+func (r *accessRequestRepo) Count(status string, search string) (int64, error) {
+	return r.q.CountAccessRequests(r.ctx, sqlc.CountAccessRequestsParams{
+		Status: exactOrAny(status),
+		Email:  fmt.Sprintf("%%%s%%", search),
+	})
+}
+
+// Review moves a pending request to status. Requests that were already
+// reviewed are left untouched.
+func (r *accessRequestRepo) Review(id uuid.UUID, status entities.AccessRequestStatus, reviewedBy string) error {
+	affected, err := r.q.ReviewAccessRequest(r.ctx, sqlc.ReviewAccessRequestParams{
+		Status:     string(status),
+		ReviewedBy: pgtype.Text{String: reviewedBy, Valid: true},
+		ID:         id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrAccessRequestAlreadyReviewed
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type apiKeyRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewApiKeyRepo(ctx context.Context, q *sqlc.Queries) repositories.ApiKeyRepository {
+	return &apiKeyRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func parseApiKey(key sqlc.ApiKey) *entities.ApiKey {
+	apiKey := &entities.ApiKey{
+		Id:        key.ID,
+		EventId:   key.EventID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		CreatedBy: key.CreatedBy,
+		CreatedAt: key.CreatedAt.Time,
+		ExpiresAt: key.ExpiresAt.Time,
+	}
+
+	if key.RevokedAt.Valid {
+		apiKey.RevokedAt = &key.RevokedAt.Time
+	}
+
+	if key.LastUsedAt.Valid {
+		apiKey.LastUsedAt = &key.LastUsedAt.Time
+	}
+
+	return apiKey
+}
+
+func (r *apiKeyRepo) Create(key *entities.ApiKey) (*entities.ApiKey, error) {
+	created, err := r.q.CreateApiKey(r.ctx, sqlc.CreateApiKeyParams{
+		EventID:   key.EventId,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		CreatedBy: key.CreatedBy,
+		ExpiresAt: pgtype.Timestamp{Time: key.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, nerrors.ErrEventNotFound
+		}
+		return nil, err
+	}
+
+	return parseApiKey(created), nil
+}
+
+func (r *apiKeyRepo) GetByHash(keyHash string) (*entities.ApiKey, error) {
+	key, err := r.q.GetApiKeyByHash(r.ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrApiKeyNotFound
+		}
+		return nil, err
+	}
+
+	return parseApiKey(key), nil
+}
+
+func (r *apiKeyRepo) GetByEventId(eventId uuid.UUID) ([]entities.ApiKey, error) {
+	keys, err := r.q.GetApiKeysByEventId(r.ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.ApiKey
+	for _, key := range keys {
+		result = append(result, *parseApiKey(key))
+	}
+
+	return result, nil
+}
+
+// Revoke returns ErrApiKeyNotFound when the key does not belong to the event
+// or has already been revoked.
+func (r *apiKeyRepo) Revoke(eventId uuid.UUID, id uuid.UUID) error {
+	affected, err := r.q.RevokeApiKey(r.ctx, sqlc.RevokeApiKeyParams{
+		RevokedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		ID:        id,
+		EventID:   eventId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrApiKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepo) Touch(id uuid.UUID) error {
+	return r.q.TouchApiKey(r.ctx, sqlc.TouchApiKeyParams{
+		LastUsedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		ID:         id,
+	})
+}
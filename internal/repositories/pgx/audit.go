@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type auditRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewAuditRepo(ctx context.Context, q *sqlc.Queries) repositories.AuditRepository {
+	return &auditRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func (r *auditRepo) Create(log *entities.AuditLog) error {
+	return r.q.CreateAuditLog(r.ctx, sqlc.CreateAuditLogParams{
+		ActorEmail: log.ActorEmail,
+		ActorRole:  log.ActorRole,
+		Action:     log.Action,
+		TargetType: log.TargetType,
+		TargetID:   log.TargetId,
+		Before:     log.Before,
+		After:      log.After,
+		Ip:         log.Ip,
+	})
+}
+
+// exactOrAny matches value exactly, or anything when value is empty.
+func exactOrAny(value string) string {
+	if value == "" {
+		return "%"
+	}
+	return value
+}
+
+type auditFilterParams struct {
+	actorEmail string
+	action     string
+	targetType string
+	targetId   string
+	from       pgtype.Timestamp
+	to         pgtype.Timestamp
+}
+
+func toAuditFilterParams(filter *entities.AuditFilter) auditFilterParams {
+	params := auditFilterParams{
+		actorEmail: fmt.Sprintf("%%%s%%", filter.ActorEmail),
+		action:     exactOrAny(filter.Action),
+		targetType: exactOrAny(filter.TargetType),
+		targetId:   exactOrAny(filter.TargetId),
+		from:       pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+		to:         pgtype.Timestamp{InfinityModifier: pgtype.Infinity, Valid: true},
+	}
+
+	if filter.From != nil {
+		params.from = pgtype.Timestamp{Time: *filter.From, Valid: true}
+	}
+
+	if filter.To != nil {
+		params.to = pgtype.Timestamp{Time: *filter.To, Valid: true}
+	}
+
+	return params
+}
+
+func (r *auditRepo) GetPagination(filter *entities.AuditFilter, pageIndex int32, pageSize int32) ([]entities.AuditLog, error) {
+	params := toAuditFilterParams(filter)
+
+	logs, err := r.q.GetAuditLogPagination(r.ctx, sqlc.GetAuditLogPaginationParams{
+		ActorEmail:  params.actorEmail,
+		Action:      params.action,
+		TargetType:  params.targetType,
+		TargetID:    params.targetId,
+		CreatedAt:   params.from,
+		CreatedAt_2: params.to,
+		Limit:       pageSize,
+		Offset:      pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.AuditLog
+	for _, log := range logs {
+		result = append(result, entities.AuditLog{
+			Id:         log.ID,
+			ActorEmail: log.ActorEmail,
+			ActorRole:  log.ActorRole,
+			Action:     log.Action,
+			TargetType: log.TargetType,
+			TargetId:   log.TargetID,
+			Before:     log.Before,
+			After:      log.After,
+			Ip:         log.Ip,
+			CreatedAt:  log.CreatedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *auditRepo) Count(filter *entities.AuditFilter) (int64, error) {
+	params := toAuditFilterParams(filter)
+
+	return r.q.CountAuditLogs(r.ctx, sqlc.CountAuditLogsParams{
+		ActorEmail:  params.actorEmail,
+		Action:      params.action,
+		TargetType:  params.targetType,
+		TargetID:    params.targetId,
+		CreatedAt:   params.from,
+		CreatedAt_2: params.to,
+	})
+}
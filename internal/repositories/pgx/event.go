@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
@@ -27,9 +28,42 @@ func NewEventRepo(ctx context.Context, q *sqlc.Queries) repositories.EventReposi
 	}
 }
 
-func (e *eventRepoImpl) GetPagination(search string, pageIndex int32, pageSize int32) ([]*entities.Event, error) {
+func parseNullableTimestamp(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func toNullableTimestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+
+	return pgtype.Timestamp{Time: *t, Valid: true}
+}
+
+func parseNullableInt4(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+
+	return &i.Int32
+}
+
+func toNullableCapacity(capacity *int32) pgtype.Int4 {
+	if capacity == nil {
+		return pgtype.Int4{}
+	}
+
+	return pgtype.Int4{Int32: *capacity, Valid: true}
+}
+
+func (e *eventRepoImpl) GetPagination(search string, status string, pageIndex int32, pageSize int32) ([]*entities.Event, error) {
 	events, err := e.q.GetAllEvents(e.ctx, sqlc.GetAllEventsParams{
 		Name:   fmt.Sprintf("%%%s%%", search),
+		Status: exactOrAny(status),
 		Offset: pageIndex * pageSize,
 		Limit:  pageSize,
 	})
@@ -38,12 +72,19 @@ func (e *eventRepoImpl) GetPagination(search string, pageIndex int32, pageSize i
 
 	for _, event := range events {
 		parsedEvent := &entities.Event{
-			Id:    event.ID,
-			Name:  event.Name,
-			Place: event.Place,
-			Date:  event.Date.Time,
-			Host:  event.Host,
-			Owner: event.FullName,
+			Id:                 event.ID,
+			Name:               event.Name,
+			Place:              event.Place,
+			Date:               event.Date.Time,
+			Host:               event.Host,
+			Owner:              event.FullName,
+			StartAt:            parseNullableTimestamp(event.StartAt),
+			EndAt:              parseNullableTimestamp(event.EndAt),
+			GraceBeforeMinutes: event.GraceBeforeMinutes,
+			GraceAfterMinutes:  event.GraceAfterMinutes,
+			Status:             entities.EventStatus(event.Status),
+			Capacity:           parseNullableInt4(event.Capacity),
+			RegistrationMode:   entities.RegistrationMode(event.RegistrationMode),
 		}
 
 		parsedEvents = append(parsedEvents, parsedEvent)
@@ -52,8 +93,11 @@ func (e *eventRepoImpl) GetPagination(search string, pageIndex int32, pageSize i
 	return parsedEvents, err
 }
 
-func (e *eventRepoImpl) GetCount(search string) (int64, error) {
-	count, err := e.q.GetEventCount(e.ctx, fmt.Sprintf("%%%s%%", search))
+func (e *eventRepoImpl) GetCount(search string, status string) (int64, error) {
+	count, err := e.q.GetEventCount(e.ctx, sqlc.GetEventCountParams{
+		Name:   fmt.Sprintf("%%%s%%", search),
+		Status: exactOrAny(status),
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -71,47 +115,59 @@ func (e *eventRepoImpl) GetById(id uuid.UUID) (*entities.Event, error) {
 	}
 
 	parsedEvent := &entities.Event{
-		Id:    event.ID,
-		Name:  event.Name,
-		Place: event.Place,
-		Date:  event.Date.Time,
-		Host:  event.Host,
-		Owner: event.FullName,
+		Id:                 event.ID,
+		Name:               event.Name,
+		Place:              event.Place,
+		Date:               event.Date.Time,
+		Host:               event.Host,
+		Owner:              event.FullName,
+		StartAt:            parseNullableTimestamp(event.StartAt),
+		EndAt:              parseNullableTimestamp(event.EndAt),
+		GraceBeforeMinutes: event.GraceBeforeMinutes,
+		GraceAfterMinutes:  event.GraceAfterMinutes,
+		Status:             entities.EventStatus(event.Status),
+		Capacity:           parseNullableInt4(event.Capacity),
+		RegistrationMode:   entities.RegistrationMode(event.RegistrationMode),
 	}
 
 	return parsedEvent, err
 }
 
-func (e *eventRepoImpl) Create(event *entities.Event, adminId string) error {
+func (e *eventRepoImpl) Create(event *entities.Event, adminId string) (uuid.UUID, error) {
 	date := pgtype.Date{}
 	date.Scan(event.Date)
 
 	parseId, err := uuid.Parse(adminId)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
-	err = e.q.CreateEvent(e.ctx, sqlc.CreateEventParams{
-		Name:    event.Name,
-		Place:   event.Place,
-		Date:    date,
-		Host:    event.Host,
-		AdminID: parseId,
+	id, err := e.q.CreateEvent(e.ctx, sqlc.CreateEventParams{
+		Name:               event.Name,
+		Place:              event.Place,
+		Date:               date,
+		Host:               event.Host,
+		AdminID:            parseId,
+		StartAt:            toNullableTimestamp(event.StartAt),
+		EndAt:              toNullableTimestamp(event.EndAt),
+		GraceBeforeMinutes: event.GraceBeforeMinutes,
+		GraceAfterMinutes:  event.GraceAfterMinutes,
+		Capacity:           toNullableCapacity(event.Capacity),
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" {
-				return nerrors.ErrEventAlreadyExists
+				return uuid.Nil, nerrors.ErrEventAlreadyExists
 			}
 			if pgErr.Code == "23503" {
-				return nerrors.ErrAdminNotFound
+				return uuid.Nil, nerrors.ErrAdminNotFound
 			}
 		}
+		return uuid.Nil, err
 	}
 
-	return nil
-
+	return id, nil
 }
 
 func (e *eventRepoImpl) DeleteById(id uuid.UUID) error {
@@ -125,11 +181,15 @@ func (e *eventRepoImpl) UpdateById(id uuid.UUID, event *entities.Event) error {
 	date.Scan(event.Date)
 
 	err := e.q.UpdateEventById(e.ctx, sqlc.UpdateEventByIdParams{
-		ID:    id,
-		Name:  event.Name,
-		Place: event.Place,
-		Date:  date,
-		Host:  event.Host,
+		ID:                 id,
+		Name:               event.Name,
+		Place:              event.Place,
+		Date:               date,
+		Host:               event.Host,
+		StartAt:            toNullableTimestamp(event.StartAt),
+		EndAt:              toNullableTimestamp(event.EndAt),
+		GraceBeforeMinutes: event.GraceBeforeMinutes,
+		GraceAfterMinutes:  event.GraceAfterMinutes,
 	})
 
 	if err != nil {
@@ -143,3 +203,76 @@ func (e *eventRepoImpl) UpdateById(id uuid.UUID, event *entities.Event) error {
 
 	return err
 }
+
+// UpdateStatus moves the event from one status to another. It returns
+// ErrEventTransition when the event is no longer in status from.
+func (e *eventRepoImpl) UpdateStatus(id uuid.UUID, from entities.EventStatus, to entities.EventStatus) error {
+	affected, err := e.q.UpdateEventStatus(e.ctx, sqlc.UpdateEventStatusParams{
+		Status:   string(to),
+		ID:       id,
+		Status_2: string(from),
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrEventTransition
+	}
+
+	return nil
+}
+
+func (e *eventRepoImpl) GetCapacity(id uuid.UUID) (*entities.EventCapacity, error) {
+	row, err := e.q.GetEventCapacity(e.ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrEventNotFound
+		}
+		return nil, err
+	}
+
+	capacity := &entities.EventCapacity{
+		Capacity: parseNullableInt4(row.Capacity),
+		Admitted: row.Admitted,
+	}
+
+	if capacity.Capacity != nil {
+		remaining := max(int64(*capacity.Capacity)-row.Admitted, 0)
+		capacity.Remaining = &remaining
+	}
+
+	return capacity, nil
+}
+
+func (e *eventRepoImpl) UpdateCapacity(id uuid.UUID, capacity *int32) error {
+	affected, err := e.q.UpdateEventCapacity(e.ctx, sqlc.UpdateEventCapacityParams{
+		Capacity: toNullableCapacity(capacity),
+		ID:       id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrEventNotFound
+	}
+
+	return nil
+}
+
+func (e *eventRepoImpl) UpdateRegistrationMode(id uuid.UUID, mode entities.RegistrationMode) error {
+	affected, err := e.q.UpdateEventRegistrationMode(e.ctx, sqlc.UpdateEventRegistrationModeParams{
+		RegistrationMode: string(mode),
+		ID:               id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrEventNotFound
+	}
+
+	return nil
+}
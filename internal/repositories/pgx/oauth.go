@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type oAuthRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewOAuthRepo(ctx context.Context, q *sqlc.Queries) repositories.OAuthRepository {
+	return &oAuthRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func (r *oAuthRepo) CreateState(state *entities.OAuthState) error {
+	return r.q.CreateOAuthState(r.ctx, sqlc.CreateOAuthStateParams{
+		State:     state.State,
+		Provider:  state.Provider,
+		Binding:   state.Binding,
+		Verifier:  state.Verifier,
+		Nonce:     state.Nonce,
+		ExpiresAt: pgtype.Timestamp{Time: state.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *oAuthRepo) TakeState(state string) (*entities.OAuthState, error) {
+	record, err := r.q.TakeOAuthState(r.ctx, state)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrInvalidOAuthState
+		}
+		return nil, err
+	}
+
+	return &entities.OAuthState{
+		State:     record.State,
+		Provider:  record.Provider,
+		Binding:   record.Binding,
+		Verifier:  record.Verifier,
+		Nonce:     record.Nonce,
+		ExpiresAt: record.ExpiresAt.Time,
+	}, nil
+}
+
+func (r *oAuthRepo) CreateExchangeCode(code *entities.ExchangeCode) error {
+	return r.q.CreateExchangeCode(r.ctx, sqlc.CreateExchangeCodeParams{
+		CodeHash:      code.CodeHash,
+		CodeChallenge: code.CodeChallenge,
+		SessionID:     code.SessionId,
+		Email:         code.Email,
+		Name:          code.Name,
+		Picture:       code.Picture,
+		Role:          code.Role,
+		ExpiresAt:     pgtype.Timestamp{Time: code.ExpiresAt, Valid: true},
+	})
+}
+
+func (r *oAuthRepo) TakeExchangeCode(codeHash string) (*entities.ExchangeCode, error) {
+	record, err := r.q.TakeExchangeCode(r.ctx, codeHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrInvalidExchangeCode
+		}
+		return nil, err
+	}
+
+	return &entities.ExchangeCode{
+		CodeHash:      record.CodeHash,
+		CodeChallenge: record.CodeChallenge,
+		SessionId:     record.SessionID,
+		Email:         record.Email,
+		Name:          record.Name,
+		Picture:       record.Picture,
+		Role:          record.Role,
+		ExpiresAt:     record.ExpiresAt.Time,
+	}, nil
+}
+
+func (r *oAuthRepo) DeleteExpired() error {
+	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+
+	err := r.q.DeleteExpiredOAuthStates(r.ctx, now)
+	if err != nil {
+		return err
+	}
+
+	return r.q.DeleteExpiredExchangeCodes(r.ctx, now)
+}
@@ -11,33 +11,164 @@ import (
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
 	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type participantRepo struct {
 	ctx context.Context
+	db  *pgxpool.Pool
 	q   *sqlc.Queries
 }
 
-func NewParticipantRepo(ctx context.Context, q *sqlc.Queries) repositories.ParticipantRepository {
+func NewParticipantRepo(ctx context.Context, db *pgxpool.Pool, q *sqlc.Queries) repositories.ParticipantRepository {
 	return &participantRepo{
 		ctx: ctx,
+		db:  db,
 		q:   q,
 	}
 }
 
-func (p *participantRepo) AddParticipant(eventId uuid.UUID, barcode string, timestamp time.Time) (*entities.Participant, error) {
+func parseParticipant(p sqlc.Participant) entities.Participant {
+	participant := entities.Participant{
+		Barcode:   p.Barcode,
+		Timestamp: p.Timestamp.Time,
+		StudentBarcode: entities.StudentBarcode{
+			StudentId: p.StudentID.String,
+			Campus:    p.Campus.String,
+			Faculty:   p.Faculty.String,
+			EntryYear: p.EntryYear.Int32,
+		},
+		ScannedBy: p.ScannedBy.String,
+		DeviceId:  p.DeviceID.String,
+	}
+
+	if p.CheckedOutAt.Valid {
+		participant.CheckedOutAt = &p.CheckedOutAt.Time
+	}
+
+	if p.ScanID != uuid.Nil {
+		participant.ScanId = &p.ScanID
+	}
+
+	if p.ApiKeyID != uuid.Nil {
+		participant.ApiKeyId = &p.ApiKeyID
+	}
+
+	return participant
+}
+
+func parseParticipantRow(row sqlc.GetParticipantPaginationRow) entities.Participant {
+	participant := parseParticipant(sqlc.Participant{
+		Barcode:      row.Barcode,
+		Timestamp:    row.Timestamp,
+		EventID:      row.EventID,
+		CheckedOutAt: row.CheckedOutAt,
+		StudentID:    row.StudentID,
+		Campus:       row.Campus,
+		Faculty:      row.Faculty,
+		EntryYear:    row.EntryYear,
+		ScanID:       row.ScanID,
+		ScannedBy:    row.ScannedBy,
+		DeviceID:     row.DeviceID,
+		ApiKeyID:     row.ApiKeyID,
+	})
+
+	if row.StudentFullName.Valid {
+		participant.Student = &entities.Student{
+			StudentId: row.StudentID.String,
+			FullName:  row.StudentFullName.String,
+			Faculty:   row.StudentFaculty.String,
+			Major:     row.StudentMajor.String,
+		}
+	}
+
+	return participant
+}
+
+func toNullableText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func toNullableInt4(i int32) pgtype.Int4 {
+	return pgtype.Int4{Int32: i, Valid: i != 0}
+}
+
+// toNullableUUID maps a missing id to uuid.Nil, which the queries store as
+// NULL.
+func toNullableUUID(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+// lockCapacity locks the event row for the rest of tx so concurrent scans are
+// admitted one at a time, and returns the event capacity.
+func (p *participantRepo) lockCapacity(qtx *sqlc.Queries, eventId uuid.UUID) (pgtype.Int4, error) {
+	capacity, err := qtx.LockEventCapacity(p.ctx, eventId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return capacity, nerrors.ErrEventNotFound
+		}
+		return capacity, err
+	}
+
+	return capacity, nil
+}
+
+// checkCapacity returns ErrEventFull when the participants recorded in tx
+// went over capacity.
+func (p *participantRepo) checkCapacity(qtx *sqlc.Queries, eventId uuid.UUID, capacity pgtype.Int4) error {
+	if !capacity.Valid {
+		return nil
+	}
+
+	count, err := qtx.CountEventParticipants(p.ctx, eventId)
+	if err != nil {
+		return err
+	}
+
+	if count > int64(capacity.Int32) {
+		return nerrors.ErrEventFull
+	}
+
+	return nil
+}
+
+func (p *participantRepo) AddParticipant(eventId uuid.UUID, participant *entities.Participant) (*entities.Participant, error) {
 	t := pgtype.Timestamp{}
-	err := t.Scan(timestamp)
+	err := t.Scan(participant.Timestamp)
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := p.q.CreateParticipantRecord(p.ctx, sqlc.CreateParticipantRecordParams{
-		Barcode:   barcode,
+	tx, err := p.db.Begin(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(p.ctx)
+
+	qtx := p.q.WithTx(tx)
+
+	capacity, err := p.lockCapacity(qtx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := qtx.CreateParticipantRecord(p.ctx, sqlc.CreateParticipantRecordParams{
+		Barcode:   participant.Barcode,
 		Timestamp: t,
 		EventID:   eventId,
+		StudentID: toNullableText(participant.StudentId),
+		Campus:    toNullableText(participant.Campus),
+		Faculty:   toNullableText(participant.Faculty),
+		EntryYear: toNullableInt4(participant.EntryYear),
+		ScannedBy: toNullableText(participant.ScannedBy),
+		DeviceID:  toNullableText(participant.DeviceId),
+		ApiKeyID:  toNullableUUID(participant.ApiKeyId),
 	})
 
 	if err != nil {
@@ -50,12 +181,142 @@ func (p *participantRepo) AddParticipant(eventId uuid.UUID, barcode string, time
 		return nil, err
 	}
 
+	err = p.checkCapacity(qtx, eventId, capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := parseParticipant(c)
+
+	return &record, nil
+}
+
+func (p *participantRepo) AddSessionParticipant(eventId uuid.UUID, sessionId uuid.UUID, participant *entities.Participant) (*entities.Participant, error) {
+	t := pgtype.Timestamp{}
+	err := t.Scan(participant.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := p.db.Begin(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(p.ctx)
+
+	qtx := p.q.WithTx(tx)
+
+	capacity, err := p.lockCapacity(qtx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := qtx.CreateSessionParticipantRecord(p.ctx, sqlc.CreateSessionParticipantRecordParams{
+		Barcode:   participant.Barcode,
+		Timestamp: t,
+		EventID:   eventId,
+		StudentID: toNullableText(participant.StudentId),
+		Campus:    toNullableText(participant.Campus),
+		Faculty:   toNullableText(participant.Faculty),
+		EntryYear: toNullableInt4(participant.EntryYear),
+		SessionID: sessionId,
+		ScannedBy: toNullableText(participant.ScannedBy),
+		DeviceID:  toNullableText(participant.DeviceId),
+		ApiKeyID:  toNullableUUID(participant.ApiKeyId),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return nil, nerrors.ErrParticipantAlreadyExists
+			}
+		}
+		return nil, err
+	}
+
+	err = p.checkCapacity(qtx, eventId, capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &entities.Participant{
-		Barcode:   c.Barcode,
-		Timestamp: c.Timestamp.Time,
+		Barcode:        c.Barcode,
+		Timestamp:      c.Timestamp.Time,
+		StudentBarcode: participant.StudentBarcode,
+		ScannedBy:      participant.ScannedBy,
+		DeviceId:       participant.DeviceId,
+		ApiKeyId:       participant.ApiKeyId,
 	}, nil
 }
 
+func (p *participantRepo) GetParticipant(eventId uuid.UUID, barcode string) (*entities.Participant, error) {
+	c, err := p.q.GetParticipantByBarcode(p.ctx, sqlc.GetParticipantByBarcodeParams{
+		EventID: eventId,
+		Barcode: barcode,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrParticipantNotFound
+		}
+		return nil, err
+	}
+
+	participant := parseParticipant(c)
+
+	return &participant, nil
+}
+
+func (p *participantRepo) GetParticipantsByBarcodes(eventId uuid.UUID, barcodes []string) ([]entities.Participant, error) {
+	records, err := p.q.GetParticipantsByBarcodes(p.ctx, sqlc.GetParticipantsByBarcodesParams{
+		EventID:  eventId,
+		Barcodes: barcodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]entities.Participant, 0, len(records))
+	for _, record := range records {
+		participants = append(participants, parseParticipant(record))
+	}
+
+	return participants, nil
+}
+
+func (p *participantRepo) CheckOutParticipant(eventId uuid.UUID, barcode string, timestamp time.Time) (*entities.Participant, error) {
+	t := pgtype.Timestamp{}
+	err := t.Scan(timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := p.q.CheckOutParticipant(p.ctx, sqlc.CheckOutParticipantParams{
+		CheckedOutAt: t,
+		EventID:      eventId,
+		Barcode:      barcode,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrParticipantAlreadyCheckedOut
+		}
+		return nil, err
+	}
+
+	participant := parseParticipant(c)
+
+	return &participant, nil
+}
+
 func (p *participantRepo) GetParticipants(eventId uuid.UUID, barcode string, pageIndex int32, pageSize int32) ([]entities.Participant, error) {
 	participants, err := p.q.GetParticipantPagination(p.ctx, sqlc.GetParticipantPaginationParams{
 		EventID: eventId,
@@ -69,10 +330,39 @@ func (p *participantRepo) GetParticipants(eventId uuid.UUID, barcode string, pag
 
 	var result []entities.Participant
 	for _, participant := range participants {
-		result = append(result, entities.Participant{
-			Barcode:   participant.Barcode,
-			Timestamp: participant.Timestamp.Time,
-		})
+		result = append(result, parseParticipantRow(participant))
+	}
+
+	return result, nil
+}
+
+// GetParticipantsAfter returns up to limit participants ordered by timestamp
+// and barcode, starting after the given participant or from the first one
+// when after is nil.
+func (p *participantRepo) GetParticipantsAfter(eventId uuid.UUID, after *entities.Participant, limit int32) ([]entities.Participant, error) {
+	t := pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	barcode := ""
+	if after != nil {
+		err := t.Scan(after.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		barcode = after.Barcode
+	}
+
+	participants, err := p.q.GetParticipantsAfter(p.ctx, sqlc.GetParticipantsAfterParams{
+		EventID:   eventId,
+		Timestamp: t,
+		Barcode:   barcode,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.Participant
+	for _, participant := range participants {
+		result = append(result, parseParticipantRow(sqlc.GetParticipantPaginationRow(participant)))
 	}
 
 	return result, nil
@@ -112,3 +402,225 @@ func (p *participantRepo) CountParticipants(eventId uuid.UUID, barcode string) (
 
 	return &count, err
 }
+
+// conflictStatus tells apart why inserting participant did nothing: a replay
+// of a recorded scan, a new scan of a barcode that is already recorded, or a
+// scan ID that belongs to a different scan.
+func (p *participantRepo) conflictStatus(qtx *sqlc.Queries, eventId uuid.UUID, participant entities.Participant) (entities.ScanStatus, error) {
+	existing, err := qtx.GetParticipantByScanId(p.ctx, *participant.ScanId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.ScanAlreadyRecorded, nil
+		}
+		return "", err
+	}
+
+	if existing.EventID == eventId && existing.Barcode == participant.Barcode {
+		return entities.ScanDuplicate, nil
+	}
+
+	return entities.ScanIdConflict, nil
+}
+
+// SyncParticipants inserts the participants in a single transaction and
+// reports, in order, whether each one was inserted or why it was not. Once
+// the event is full the remaining new participants are reported as
+// event_full.
+func (p *participantRepo) SyncParticipants(eventId uuid.UUID, participants []entities.Participant) ([]entities.ScanStatus, error) {
+	tx, err := p.db.Begin(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(p.ctx)
+
+	qtx := p.q.WithTx(tx)
+
+	capacity, err := p.lockCapacity(qtx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if capacity.Valid {
+		count, err = qtx.CountEventParticipants(p.ctx, eventId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]entities.ScanStatus, len(participants))
+	for i, participant := range participants {
+		t := pgtype.Timestamp{}
+		err := t.Scan(participant.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		params := sqlc.CreateParticipantRecordIfNotExistsParams{
+			Barcode:   participant.Barcode,
+			Timestamp: t,
+			EventID:   eventId,
+			StudentID: toNullableText(participant.StudentId),
+			Campus:    toNullableText(participant.Campus),
+			Faculty:   toNullableText(participant.Faculty),
+			EntryYear: toNullableInt4(participant.EntryYear),
+			ScanID:    *participant.ScanId,
+			ScannedBy: toNullableText(participant.ScannedBy),
+			DeviceID:  toNullableText(participant.DeviceId),
+			ApiKeyID:  toNullableUUID(participant.ApiKeyId),
+		}
+
+		full := capacity.Valid && count >= int64(capacity.Int32)
+		if full {
+			// Try the insert in a savepoint only to tell conflicts apart
+			sp, err := tx.Begin(p.ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = p.q.WithTx(sp).CreateParticipantRecordIfNotExists(p.ctx, params)
+			sp.Rollback(p.ctx)
+
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				statuses[i], err = p.conflictStatus(qtx, eventId, participant)
+				if err != nil {
+					return nil, err
+				}
+			case err != nil:
+				return nil, err
+			default:
+				statuses[i] = entities.ScanEventFull
+			}
+			continue
+		}
+
+		_, err = qtx.CreateParticipantRecordIfNotExists(p.ctx, params)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				statuses[i], err = p.conflictStatus(qtx, eventId, participant)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		statuses[i] = entities.ScanInserted
+		count++
+	}
+
+	err = tx.Commit(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// toDateRange turns optional bounds into an inclusive date range, leaving a
+// missing bound open.
+func toDateRange(from *time.Time, to *time.Time) (pgtype.Date, pgtype.Date) {
+	start := pgtype.Date{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	if from != nil {
+		start = pgtype.Date{Time: *from, Valid: true}
+	}
+
+	end := pgtype.Date{InfinityModifier: pgtype.Infinity, Valid: true}
+	if to != nil {
+		end = pgtype.Date{Time: *to, Valid: true}
+	}
+
+	return start, end
+}
+
+func (p *participantRepo) GetAttendances(barcode string, from *time.Time, to *time.Time, pageIndex int32, pageSize int32) ([]entities.Attendance, error) {
+	start, end := toDateRange(from, to)
+
+	attendances, err := p.q.GetAttendancesByBarcode(p.ctx, sqlc.GetAttendancesByBarcodeParams{
+		Barcode: barcode,
+		Date:    start,
+		Date_2:  end,
+		Limit:   pageSize,
+		Offset:  pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.Attendance
+	for _, attendance := range attendances {
+		result = append(result, entities.Attendance{
+			Event: entities.AttendanceEvent{
+				Id:    attendance.EventID,
+				Name:  attendance.EventName,
+				Place: attendance.EventPlace,
+				Date:  attendance.EventDate.Time,
+				Host:  attendance.EventHost,
+			},
+			Timestamp:    attendance.Timestamp.Time,
+			CheckedOutAt: parseNullableTimestamp(attendance.CheckedOutAt),
+		})
+	}
+
+	return result, nil
+}
+
+func (p *participantRepo) GetAttendanceSummary(barcode string, from *time.Time, to *time.Time) (*entities.AttendanceSummary, error) {
+	start, end := toDateRange(from, to)
+
+	summary, err := p.q.GetAttendanceSummaryByBarcode(p.ctx, sqlc.GetAttendanceSummaryByBarcodeParams{
+		Barcode: barcode,
+		Date:    start,
+		Date_2:  end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AttendanceSummary{
+		TotalEvents:       summary.TotalEvents,
+		TotalDwellSeconds: summary.TotalDwellSeconds,
+	}, nil
+}
+
+func (p *participantRepo) GetStaffScanCounts(eventId uuid.UUID) ([]entities.StaffScanCount, error) {
+	counts, err := p.q.GetStaffScanCounts(p.ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.StaffScanCount
+	for _, count := range counts {
+		result = append(result, entities.StaffScanCount{
+			ScannedBy:   count.ScannedBy,
+			Scans:       count.Scans,
+			FirstScanAt: count.FirstScanAt.Time,
+			LastScanAt:  count.LastScanAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (p *participantRepo) GetStaffScanTimeline(eventId uuid.UUID, interval string) ([]entities.StaffScanBucket, error) {
+	buckets, err := p.q.GetStaffScanTimeline(p.ctx, sqlc.GetStaffScanTimelineParams{
+		EventID:   eventId,
+		DateTrunc: interval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.StaffScanBucket
+	for _, bucket := range buckets {
+		result = append(result, entities.StaffScanBucket{
+			ScannedBy: bucket.ScannedBy,
+			Bucket:    bucket.Bucket.Time,
+			Scans:     bucket.Scans,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type registrationRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewRegistrationRepo(ctx context.Context, q *sqlc.Queries) repositories.RegistrationRepository {
+	return &registrationRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+// Create registers the students for the event and returns how many were
+// added, students that are already registered are left as they are.
+func (r *registrationRepo) Create(eventId uuid.UUID, studentIds []string, createdBy string) (int64, error) {
+	count, err := r.q.CreateRegistrations(r.ctx, sqlc.CreateRegistrationsParams{
+		EventID:    eventId,
+		StudentIds: studentIds,
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23503" {
+				return 0, nerrors.ErrEventNotFound
+			}
+		}
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *registrationRepo) GetPagination(eventId uuid.UUID, search string, pageIndex int32, pageSize int32) ([]entities.Registration, error) {
+	rows, err := r.q.GetRegistrationsPagination(r.ctx, sqlc.GetRegistrationsPaginationParams{
+		EventID:   eventId,
+		StudentID: fmt.Sprintf("%%%s%%", search),
+		Limit:     pageSize,
+		Offset:    pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []entities.Registration
+	for _, row := range rows {
+		registration := entities.Registration{
+			StudentId: row.StudentID,
+			Attended:  row.Attended,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt.Time,
+		}
+
+		if row.StudentFullName.Valid {
+			fullName := row.StudentFullName.String
+			registration.FullName = &fullName
+		}
+
+		registrations = append(registrations, registration)
+	}
+
+	return registrations, nil
+}
+
+func (r *registrationRepo) Count(eventId uuid.UUID, search string) (int64, error) {
+	return r.q.GetRegistrationCount(r.ctx, sqlc.GetRegistrationCountParams{
+		EventID:   eventId,
+		StudentID: fmt.Sprintf("%%%s%%", search),
+	})
+}
+
+// GetRegistered returns which of studentIds are registered for the event.
+func (r *registrationRepo) GetRegistered(eventId uuid.UUID, studentIds []string) ([]string, error) {
+	return r.q.GetRegisteredStudentIds(r.ctx, sqlc.GetRegisteredStudentIdsParams{
+		EventID:    eventId,
+		StudentIds: studentIds,
+	})
+}
+
+func (r *registrationRepo) Delete(eventId uuid.UUID, studentId string) error {
+	affected, err := r.q.DeleteRegistration(r.ctx, sqlc.DeleteRegistrationParams{
+		EventID:   eventId,
+		StudentID: studentId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrRegistrationNotFound
+	}
+
+	return nil
+}
+
+func (r *registrationRepo) GetSummary(eventId uuid.UUID) (*entities.RegistrationSummary, error) {
+	row, err := r.q.GetRegistrationSummary(r.ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.RegistrationSummary{
+		Registered: row.Registered,
+		Attended:   row.Attended,
+		NoShows:    row.Registered - row.Attended,
+		WalkIns:    row.WalkIns,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type sessionRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewSessionRepo(ctx context.Context, q *sqlc.Queries) repositories.SessionRepository {
+	return &sessionRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func (r *sessionRepo) GetAllFromEvent(eventId uuid.UUID) ([]*entities.Session, error) {
+	sessions, err := r.q.GetSessionsByEventId(r.ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Session
+	for _, session := range sessions {
+		result = append(result, &entities.Session{
+			Id:                session.ID,
+			EventId:           session.EventID,
+			Name:              session.Name,
+			StartAt:           session.StartAt.Time,
+			EndAt:             session.EndAt.Time,
+			ParticipantsCount: session.ParticipantsCount,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *sessionRepo) GetById(id uuid.UUID) (*entities.Session, error) {
+	session, err := r.q.GetSessionById(r.ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &entities.Session{
+		Id:      session.ID,
+		EventId: session.EventID,
+		Name:    session.Name,
+		StartAt: session.StartAt.Time,
+		EndAt:   session.EndAt.Time,
+	}, nil
+}
+
+func (r *sessionRepo) CountFromEvent(eventId uuid.UUID) (int64, error) {
+	return r.q.CountSessionsByEventId(r.ctx, eventId)
+}
+
+func (r *sessionRepo) Create(s *entities.Session) (*entities.Session, error) {
+	session, err := r.q.CreateSession(r.ctx, sqlc.CreateSessionParams{
+		EventID: s.EventId,
+		Name:    s.Name,
+		StartAt: pgtype.Timestamp{Time: s.StartAt, Valid: true},
+		EndAt:   pgtype.Timestamp{Time: s.EndAt, Valid: true},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23503" {
+				return nil, nerrors.ErrEventNotFound
+			}
+		}
+		return nil, err
+	}
+
+	return &entities.Session{
+		Id:      session.ID,
+		EventId: session.EventID,
+		Name:    session.Name,
+		StartAt: session.StartAt.Time,
+		EndAt:   session.EndAt.Time,
+	}, nil
+}
+
+func (r *sessionRepo) UpdateById(id uuid.UUID, s *entities.Session) error {
+	return r.q.UpdateSessionById(r.ctx, sqlc.UpdateSessionByIdParams{
+		ID:      id,
+		Name:    s.Name,
+		StartAt: pgtype.Timestamp{Time: s.StartAt, Valid: true},
+		EndAt:   pgtype.Timestamp{Time: s.EndAt, Valid: true},
+	})
+}
+
+func (r *sessionRepo) DeleteById(id uuid.UUID) error {
+	return r.q.DeleteSessionById(r.ctx, id)
+}
+
+func (r *sessionRepo) GetAttendance(eventId uuid.UUID, barcode string, pageIndex int32, pageSize int32) ([]entities.SessionAttendance, error) {
+	records, err := r.q.GetSessionAttendancePagination(r.ctx, sqlc.GetSessionAttendancePaginationParams{
+		EventID: eventId,
+		Barcode: fmt.Sprintf("%%%s%%", barcode),
+		Limit:   pageSize,
+		Offset:  pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.SessionAttendance
+	for _, record := range records {
+		result = append(result, entities.SessionAttendance{
+			Barcode:          record.Barcode,
+			SessionsAttended: record.SessionsAttended,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *sessionRepo) CountAttendance(eventId uuid.UUID, barcode string) (int64, error) {
+	return r.q.CountSessionAttendance(r.ctx, sqlc.CountSessionAttendanceParams{
+		EventID: eventId,
+		Barcode: fmt.Sprintf("%%%s%%", barcode),
+	})
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type signingKeyRepo struct {
+	ctx context.Context
+	q   *sqlc.Queries
+}
+
+func NewSigningKeyRepo(ctx context.Context, q *sqlc.Queries) repositories.SigningKeyRepository {
+	return &signingKeyRepo{
+		ctx: ctx,
+		q:   q,
+	}
+}
+
+func (r *signingKeyRepo) Create(key *entities.SigningKey) error {
+	return r.q.CreateSigningKey(r.ctx, sqlc.CreateSigningKeyParams{
+		Kid:        key.Kid,
+		Algorithm:  key.Algorithm,
+		PrivateKey: key.PrivateKey,
+		CreatedAt:  pgtype.Timestamp{Time: key.CreatedAt, Valid: true},
+		ExpiresAt:  pgtype.Timestamp{Time: key.ExpiresAt, Valid: true},
+		ActiveFrom: pgtype.Timestamp{Time: key.ActiveFrom, Valid: true},
+	})
+}
+
+// GetActive returns the keys that have not expired, latest ActiveFrom
+// first.
+func (r *signingKeyRepo) GetActive() ([]entities.SigningKey, error) {
+	keys, err := r.q.GetActiveSigningKeys(r.ctx, pgtype.Timestamp{Time: time.Now(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.SigningKey
+	for _, key := range keys {
+		result = append(result, entities.SigningKey{
+			Kid:        key.Kid,
+			Algorithm:  key.Algorithm,
+			PrivateKey: key.PrivateKey,
+			CreatedAt:  key.CreatedAt.Time,
+			ExpiresAt:  key.ExpiresAt.Time,
+			ActiveFrom: key.ActiveFrom.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *signingKeyRepo) DeleteExpired() error {
+	return r.q.DeleteExpiredSigningKeys(r.ctx, pgtype.Timestamp{Time: time.Now(), Valid: true})
+}
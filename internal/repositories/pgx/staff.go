@@ -11,30 +11,62 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type staffRepository struct {
 	ctx context.Context
+	db  *pgxpool.Pool
 	q   *sqlc.Queries
 }
 
-func NewStaffRepository(ctx context.Context, q *sqlc.Queries) repositories.StaffRepository {
+func NewStaffRepository(ctx context.Context, db *pgxpool.Pool, q *sqlc.Queries) repositories.StaffRepository {
 	return &staffRepository{
 		ctx: ctx,
+		db:  db,
 		q:   q,
 	}
 }
 
-func (s *staffRepository) AddStaffs(email []string, eventId uuid.UUID) error {
-	var staffs []sqlc.CreateStaffsRecordParams
-	for _, e := range email {
-		staffs = append(staffs, sqlc.CreateStaffsRecordParams{
-			Email:   e,
+// ReplaceAll swaps the staff of the event for staffs in one transaction, so a
+// failed insert leaves the previous staff in place.
+func (s *staffRepository) ReplaceAll(staffs []entities.Staff, eventId uuid.UUID) error {
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(s.ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	err = qtx.DeleteAllStaffFromEvent(s.ctx, eventId)
+	if err != nil {
+		return err
+	}
+
+	err = s.addStaffs(qtx, staffs, eventId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(s.ctx)
+}
+
+func (s *staffRepository) addStaffs(qtx *sqlc.Queries, staffs []entities.Staff, eventId uuid.UUID) error {
+	if len(staffs) == 0 {
+		return nil
+	}
+
+	var records []sqlc.CreateStaffsRecordParams
+	for _, staff := range staffs {
+		records = append(records, sqlc.CreateStaffsRecordParams{
+			Email:   staff.Email,
 			EventID: eventId,
+			Role:    string(staff.Role),
 		})
 	}
 
-	_, err := s.q.CreateStaffsRecord(s.ctx, staffs)
+	_, err := qtx.CreateStaffsRecord(s.ctx, records)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -51,8 +83,19 @@ func (s *staffRepository) AddStaffs(email []string, eventId uuid.UUID) error {
 	return err
 }
 
-func (s *staffRepository) DeleteAll(eventId uuid.UUID) error {
-	err := s.q.DeleteAllStaffFromEvent(s.ctx, eventId)
+func (s *staffRepository) Upsert(staff *entities.Staff, eventId uuid.UUID) error {
+	err := s.q.UpsertStaff(s.ctx, sqlc.UpsertStaffParams{
+		Email:   staff.Email,
+		EventID: eventId,
+		Role:    string(staff.Role),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nerrors.ErrEventNotFound
+		}
+	}
+
 	return err
 }
 
@@ -69,6 +112,7 @@ func (s *staffRepository) GetAllFromEvent(id *uuid.UUID) ([]*entities.Staff, err
 	for _, staff := range staffs {
 		result = append(result, &entities.Staff{
 			Email: staff.Email,
+			Role:  entities.StaffRole(staff.Role),
 		})
 	}
 
@@ -89,6 +133,7 @@ func (s *staffRepository) GetByEmail(email string) ([]entities.Staff, error) {
 	for _, staff := range staffs {
 		parsedStaff := &entities.Staff{
 			Email: staff.Email,
+			Role:  entities.StaffRole(staff.Role),
 		}
 		parsedStaffs = append(parsedStaffs, *parsedStaff)
 	}
@@ -111,5 +156,6 @@ func (s *staffRepository) GetByEmailAndEventId(email string, eventId uuid.UUID)
 
 	return &entities.Staff{
 		Email: staff.Email,
+		Role:  entities.StaffRole(staff.Role),
 	}, nil
 }
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/entities"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
+	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type studentRepo struct {
+	ctx context.Context
+	db  *pgxpool.Pool
+	q   *sqlc.Queries
+}
+
+func NewStudentRepo(ctx context.Context, db *pgxpool.Pool, q *sqlc.Queries) repositories.StudentRepository {
+	return &studentRepo{
+		ctx: ctx,
+		db:  db,
+		q:   q,
+	}
+}
+
+// Upsert writes the whole roster in one transaction, so a failing row leaves
+// the students as they were.
+func (r *studentRepo) Upsert(students []entities.Student) error {
+	payload := make([]sqlc.UpsertStudentsParams, 0, len(students))
+	for _, student := range students {
+		payload = append(payload, sqlc.UpsertStudentsParams{
+			StudentID: student.StudentId,
+			FullName:  student.FullName,
+			Faculty:   student.Faculty,
+			Major:     student.Major,
+		})
+	}
+
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(r.ctx)
+
+	op := r.q.WithTx(tx).UpsertStudents(r.ctx, payload)
+
+	op.Exec(func(i int, _err error) {
+		if _err != nil && err == nil {
+			err = _err
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(r.ctx)
+}
+
+func (r *studentRepo) GetById(studentId string) (*entities.Student, error) {
+	student, err := r.q.GetStudentById(r.ctx, studentId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nerrors.ErrStudentNotFound
+		}
+		return nil, err
+	}
+
+	return &entities.Student{
+		StudentId: student.StudentID,
+		FullName:  student.FullName,
+		Faculty:   student.Faculty,
+		Major:     student.Major,
+	}, nil
+}
+
+func (r *studentRepo) GetPagination(search string, pageIndex int32, pageSize int32) ([]entities.Student, error) {
+	students, err := r.q.GetStudentsPagination(r.ctx, sqlc.GetStudentsPaginationParams{
+		StudentID: fmt.Sprintf("%%%s%%", search),
+		Limit:     pageSize,
+		Offset:    pageIndex * pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.Student
+	for _, student := range students {
+		result = append(result, entities.Student{
+			StudentId: student.StudentID,
+			FullName:  student.FullName,
+			Faculty:   student.Faculty,
+			Major:     student.Major,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *studentRepo) Count(search string) (int64, error) {
+	return r.q.CountStudents(r.ctx, fmt.Sprintf("%%%s%%", search))
+}
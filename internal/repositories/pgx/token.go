@@ -7,6 +7,7 @@ import (
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/nerrors"
 	"github.com/SornchaiTheDev/nisit-scan-backend/domain/repositories"
 	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -22,24 +23,53 @@ func NewTokenRepository(ctx context.Context, q *sqlc.Queries) repositories.Token
 	}
 }
 
-func (r *tokenRepo) GetRefreshToken(email string) (*entities.RefreshToken, error) {
-	record, err := r.q.GetRefreshToken(r.ctx, email)
+func parseRefreshToken(record sqlc.RefreshToken) entities.RefreshToken {
+	return entities.RefreshToken{
+		Id:         record.ID,
+		Email:      record.Email,
+		TokenHash:  record.TokenHash,
+		UserAgent:  record.UserAgent,
+		Ip:         record.Ip,
+		CreatedAt:  record.CreatedAt.Time,
+		LastUsedAt: record.LastUsedAt.Time,
+	}
+}
+
+func (r *tokenRepo) GetRefreshToken(id uuid.UUID) (*entities.RefreshToken, error) {
+	record, err := r.q.GetRefreshToken(r.ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nerrors.ErrTokenNotFound
 		}
+		return nil, err
 	}
 
-	return &entities.RefreshToken{
-		Token: record.Token,
-		Email: record.Email,
-	}, nil
+	token := parseRefreshToken(record)
+
+	return &token, nil
 }
 
-func (r *tokenRepo) AddRefreshToken(email string, token string) error {
+func (r *tokenRepo) GetRefreshTokensByEmail(email string) ([]entities.RefreshToken, error) {
+	records, err := r.q.GetRefreshTokensByEmail(r.ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []entities.RefreshToken
+	for _, record := range records {
+		result = append(result, parseRefreshToken(record))
+	}
+
+	return result, nil
+}
+
+func (r *tokenRepo) AddRefreshToken(token *entities.RefreshToken) error {
 	err := r.q.CreateRefreshToken(r.ctx, sqlc.CreateRefreshTokenParams{
-		Email: email,
-		Token: token,
+		ID:        token.Id,
+		Email:     token.Email,
+		TokenHash: token.TokenHash,
+		UserAgent: token.UserAgent,
+		Ip:        token.Ip,
 	})
 	if err != nil {
 		return err
@@ -48,11 +78,61 @@ func (r *tokenRepo) AddRefreshToken(email string, token string) error {
 	return nil
 }
 
-func (r *tokenRepo) RemoveRefreshToken(email string) error {
-	err := r.q.DeleteRefreshToken(r.ctx, email)
+// RotateRefreshToken replaces the stored hash only if it is still
+// previousHash, so two refreshes racing with the same token cannot both win.
+func (r *tokenRepo) RotateRefreshToken(token *entities.RefreshToken, previousHash string) error {
+	affected, err := r.q.RotateRefreshToken(r.ctx, sqlc.RotateRefreshTokenParams{
+		TokenHash:   token.TokenHash,
+		UserAgent:   token.UserAgent,
+		Ip:          token.Ip,
+		ID:          token.Id,
+		TokenHash_2: previousHash,
+	})
 	if err != nil {
 		return err
 	}
 
+	if affected == 0 {
+		return nerrors.ErrTokenNotMatch
+	}
+
 	return nil
 }
+
+func (r *tokenRepo) RemoveRefreshToken(id uuid.UUID, email string) error {
+	affected, err := r.q.DeleteRefreshToken(r.ctx, sqlc.DeleteRefreshTokenParams{
+		ID:    id,
+		Email: email,
+	})
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return nerrors.ErrTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *tokenRepo) RemoveRefreshTokensByEmail(email string) error {
+	return r.q.DeleteRefreshTokensByEmail(r.ctx, email)
+}
+
+// GetTokenVersion returns the version access tokens of email must carry. A
+// user whose tokens were never revoked is on version 0.
+func (r *tokenRepo) GetTokenVersion(email string) (int32, error) {
+	version, err := r.q.GetTokenVersion(r.ctx, email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *tokenRepo) BumpTokenVersion(email string) error {
+	return r.q.BumpTokenVersion(r.ctx, email)
+}
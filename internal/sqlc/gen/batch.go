@@ -69,6 +69,11 @@ func (b *DeleteAdminByIdsBatchResults) Close() error {
 }
 
 const deleteParticipantsByBarcode = `-- name: DeleteParticipantsByBarcode :batchexec
+WITH deleted_session_participants AS (
+	DELETE FROM session_participants
+	WHERE session_participants.barcode = $1
+	AND session_participants.session_id IN (SELECT event_sessions.id FROM event_sessions WHERE event_sessions.event_id = $2)
+)
 DELETE FROM participants WHERE barcode = $1 AND event_id = $2
 `
 
@@ -116,3 +121,58 @@ func (b *DeleteParticipantsByBarcodeBatchResults) Close() error {
 	b.closed = true
 	return b.br.Close()
 }
+
+const upsertStudents = `-- name: UpsertStudents :batchexec
+INSERT INTO students (student_id,full_name,faculty,major) VALUES ($1,$2,$3,$4)
+ON CONFLICT (student_id) DO UPDATE
+SET full_name = EXCLUDED.full_name, faculty = EXCLUDED.faculty, major = EXCLUDED.major, updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertStudentsBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpsertStudentsParams struct {
+	StudentID string
+	FullName  string
+	Faculty   string
+	Major     string
+}
+
+func (q *Queries) UpsertStudents(ctx context.Context, arg []UpsertStudentsParams) *UpsertStudentsBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.StudentID,
+			a.FullName,
+			a.Faculty,
+			a.Major,
+		}
+		batch.Queue(upsertStudents, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpsertStudentsBatchResults{br, len(arg), false}
+}
+
+func (b *UpsertStudentsBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpsertStudentsBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
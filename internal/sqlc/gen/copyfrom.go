@@ -31,6 +31,7 @@ func (r iteratorForCreateStaffsRecord) Values() ([]interface{}, error) {
 	return []interface{}{
 		r.rows[0].Email,
 		r.rows[0].EventID,
+		r.rows[0].Role,
 	}, nil
 }
 
@@ -39,5 +40,5 @@ func (r iteratorForCreateStaffsRecord) Err() error {
 }
 
 func (q *Queries) CreateStaffsRecord(ctx context.Context, arg []CreateStaffsRecordParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"staffs"}, []string{"email", "event_id"}, &iteratorForCreateStaffsRecord{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"staffs"}, []string{"email", "event_id", "role"}, &iteratorForCreateStaffsRecord{rows: arg})
 }
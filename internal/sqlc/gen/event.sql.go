@@ -12,27 +12,40 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createEvent = `-- name: CreateEvent :exec
-INSERT INTO events (name,place,date,host,admin_id) VALUES ($1,$2,$3,$4,$5)
+const createEvent = `-- name: CreateEvent :one
+INSERT INTO events (name,place,date,host,admin_id,start_at,end_at,grace_before_minutes,grace_after_minutes,capacity) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+RETURNING id
 `
 
 type CreateEventParams struct {
-	Name    string
-	Place   string
-	Date    pgtype.Date
-	Host    string
-	AdminID uuid.UUID
+	Name               string
+	Place              string
+	Date               pgtype.Date
+	Host               string
+	AdminID            uuid.UUID
+	StartAt            pgtype.Timestamp
+	EndAt              pgtype.Timestamp
+	GraceBeforeMinutes int32
+	GraceAfterMinutes  int32
+	Capacity           pgtype.Int4
 }
 
-func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error {
-	_, err := q.db.Exec(ctx, createEvent,
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createEvent,
 		arg.Name,
 		arg.Place,
 		arg.Date,
 		arg.Host,
 		arg.AdminID,
+		arg.StartAt,
+		arg.EndAt,
+		arg.GraceBeforeMinutes,
+		arg.GraceAfterMinutes,
+		arg.Capacity,
 	)
-	return err
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
 const deleteEventById = `-- name: DeleteEventById :exec
@@ -45,35 +58,48 @@ func (q *Queries) DeleteEventById(ctx context.Context, id uuid.UUID) error {
 }
 
 const getAllEvents = `-- name: GetAllEvents :many
-SELECT events.id, name, place, date, host, admin_id, created_at, admins.id, email, full_name, deleted_at FROM events
+SELECT events.id, name, place, date, host, admin_id, created_at, start_at, end_at, grace_before_minutes, grace_after_minutes, status, capacity, registration_mode, admins.id, email, full_name, deleted_at FROM events
 INNER JOIN admins ON events.admin_id = admins.id
-WHERE events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1
+WHERE (events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1) AND events.status LIKE $2
 ORDER BY (events.date,events.created_at) DESC
-LIMIT $2 OFFSET $3
+LIMIT $3 OFFSET $4
 `
 
 type GetAllEventsParams struct {
 	Name   string
+	Status string
 	Limit  int32
 	Offset int32
 }
 
 type GetAllEventsRow struct {
-	ID        uuid.UUID
-	Name      string
-	Place     string
-	Date      pgtype.Date
-	Host      string
-	AdminID   uuid.UUID
-	CreatedAt pgtype.Timestamp
-	ID_2      uuid.UUID
-	Email     string
-	FullName  string
-	DeletedAt pgtype.Timestamp
+	ID                 uuid.UUID
+	Name               string
+	Place              string
+	Date               pgtype.Date
+	Host               string
+	AdminID            uuid.UUID
+	CreatedAt          pgtype.Timestamp
+	StartAt            pgtype.Timestamp
+	EndAt              pgtype.Timestamp
+	GraceBeforeMinutes int32
+	GraceAfterMinutes  int32
+	Status             string
+	Capacity           pgtype.Int4
+	RegistrationMode   string
+	ID_2               uuid.UUID
+	Email              string
+	FullName           string
+	DeletedAt          pgtype.Timestamp
 }
 
 func (q *Queries) GetAllEvents(ctx context.Context, arg GetAllEventsParams) ([]GetAllEventsRow, error) {
-	rows, err := q.db.Query(ctx, getAllEvents, arg.Name, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getAllEvents,
+		arg.Name,
+		arg.Status,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +115,13 @@ func (q *Queries) GetAllEvents(ctx context.Context, arg GetAllEventsParams) ([]G
 			&i.Host,
 			&i.AdminID,
 			&i.CreatedAt,
+			&i.StartAt,
+			&i.EndAt,
+			&i.GraceBeforeMinutes,
+			&i.GraceAfterMinutes,
+			&i.Status,
+			&i.Capacity,
+			&i.RegistrationMode,
 			&i.ID_2,
 			&i.Email,
 			&i.FullName,
@@ -105,23 +138,30 @@ func (q *Queries) GetAllEvents(ctx context.Context, arg GetAllEventsParams) ([]G
 }
 
 const getEventById = `-- name: GetEventById :one
-SELECT events.id, name, place, date, host, admin_id, created_at, admins.id, email, full_name, deleted_at FROM events
+SELECT events.id, name, place, date, host, admin_id, created_at, start_at, end_at, grace_before_minutes, grace_after_minutes, status, capacity, registration_mode, admins.id, email, full_name, deleted_at FROM events
 INNER JOIN admins ON events.admin_id = admins.id
 WHERE events.id = $1
 `
 
 type GetEventByIdRow struct {
-	ID        uuid.UUID
-	Name      string
-	Place     string
-	Date      pgtype.Date
-	Host      string
-	AdminID   uuid.UUID
-	CreatedAt pgtype.Timestamp
-	ID_2      uuid.UUID
-	Email     string
-	FullName  string
-	DeletedAt pgtype.Timestamp
+	ID                 uuid.UUID
+	Name               string
+	Place              string
+	Date               pgtype.Date
+	Host               string
+	AdminID            uuid.UUID
+	CreatedAt          pgtype.Timestamp
+	StartAt            pgtype.Timestamp
+	EndAt              pgtype.Timestamp
+	GraceBeforeMinutes int32
+	GraceAfterMinutes  int32
+	Status             string
+	Capacity           pgtype.Int4
+	RegistrationMode   string
+	ID_2               uuid.UUID
+	Email              string
+	FullName           string
+	DeletedAt          pgtype.Timestamp
 }
 
 func (q *Queries) GetEventById(ctx context.Context, id uuid.UUID) (GetEventByIdRow, error) {
@@ -135,6 +175,13 @@ func (q *Queries) GetEventById(ctx context.Context, id uuid.UUID) (GetEventByIdR
 		&i.Host,
 		&i.AdminID,
 		&i.CreatedAt,
+		&i.StartAt,
+		&i.EndAt,
+		&i.GraceBeforeMinutes,
+		&i.GraceAfterMinutes,
+		&i.Status,
+		&i.Capacity,
+		&i.RegistrationMode,
 		&i.ID_2,
 		&i.Email,
 		&i.FullName,
@@ -143,30 +190,68 @@ func (q *Queries) GetEventById(ctx context.Context, id uuid.UUID) (GetEventByIdR
 	return i, err
 }
 
+const getEventCapacity = `-- name: GetEventCapacity :one
+SELECT events.capacity, (SELECT COUNT(*) FROM participants WHERE participants.event_id = events.id) AS admitted
+FROM events
+WHERE events.id = $1
+`
+
+type GetEventCapacityRow struct {
+	Capacity pgtype.Int4
+	Admitted int64
+}
+
+func (q *Queries) GetEventCapacity(ctx context.Context, id uuid.UUID) (GetEventCapacityRow, error) {
+	row := q.db.QueryRow(ctx, getEventCapacity, id)
+	var i GetEventCapacityRow
+	err := row.Scan(&i.Capacity, &i.Admitted)
+	return i, err
+}
+
 const getEventCount = `-- name: GetEventCount :one
 SELECT COUNT(*) FROM events
-WHERE events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1
+WHERE (events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1) AND events.status LIKE $2
 `
 
-func (q *Queries) GetEventCount(ctx context.Context, name string) (int64, error) {
-	row := q.db.QueryRow(ctx, getEventCount, name)
+type GetEventCountParams struct {
+	Name   string
+	Status string
+}
+
+func (q *Queries) GetEventCount(ctx context.Context, arg GetEventCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getEventCount, arg.Name, arg.Status)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const lockEventCapacity = `-- name: LockEventCapacity :one
+SELECT capacity FROM events WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockEventCapacity(ctx context.Context, id uuid.UUID) (pgtype.Int4, error) {
+	row := q.db.QueryRow(ctx, lockEventCapacity, id)
+	var capacity pgtype.Int4
+	err := row.Scan(&capacity)
+	return capacity, err
+}
+
 const updateEventById = `-- name: UpdateEventById :exec
 UPDATE events
-SET name = $1, place = $2, date = $3, host = $4
-WHERE id = $5
+SET name = $1, place = $2, date = $3, host = $4, start_at = $5, end_at = $6, grace_before_minutes = $7, grace_after_minutes = $8
+WHERE id = $9
 `
 
 type UpdateEventByIdParams struct {
-	Name  string
-	Place string
-	Date  pgtype.Date
-	Host  string
-	ID    uuid.UUID
+	Name               string
+	Place              string
+	Date               pgtype.Date
+	Host               string
+	StartAt            pgtype.Timestamp
+	EndAt              pgtype.Timestamp
+	GraceBeforeMinutes int32
+	GraceAfterMinutes  int32
+	ID                 uuid.UUID
 }
 
 func (q *Queries) UpdateEventById(ctx context.Context, arg UpdateEventByIdParams) error {
@@ -175,7 +260,63 @@ func (q *Queries) UpdateEventById(ctx context.Context, arg UpdateEventByIdParams
 		arg.Place,
 		arg.Date,
 		arg.Host,
+		arg.StartAt,
+		arg.EndAt,
+		arg.GraceBeforeMinutes,
+		arg.GraceAfterMinutes,
 		arg.ID,
 	)
 	return err
 }
+
+const updateEventCapacity = `-- name: UpdateEventCapacity :execrows
+UPDATE events SET capacity = $1 WHERE id = $2
+`
+
+type UpdateEventCapacityParams struct {
+	Capacity pgtype.Int4
+	ID       uuid.UUID
+}
+
+func (q *Queries) UpdateEventCapacity(ctx context.Context, arg UpdateEventCapacityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEventCapacity, arg.Capacity, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateEventRegistrationMode = `-- name: UpdateEventRegistrationMode :execrows
+UPDATE events SET registration_mode = $1 WHERE id = $2
+`
+
+type UpdateEventRegistrationModeParams struct {
+	RegistrationMode string
+	ID               uuid.UUID
+}
+
+func (q *Queries) UpdateEventRegistrationMode(ctx context.Context, arg UpdateEventRegistrationModeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEventRegistrationMode, arg.RegistrationMode, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateEventStatus = `-- name: UpdateEventStatus :execrows
+UPDATE events SET status = $1 WHERE id = $2 AND status = $3
+`
+
+type UpdateEventStatusParams struct {
+	Status   string
+	ID       uuid.UUID
+	Status_2 string
+}
+
+func (q *Queries) UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEventStatus, arg.Status, arg.ID, arg.Status_2)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -9,6 +9,16 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccessRequest struct {
+	ID         uuid.UUID
+	Email      string
+	FullName   string
+	Status     string
+	ReviewedBy pgtype.Text
+	ReviewedAt pgtype.Timestamp
+	CreatedAt  pgtype.Timestamp
+}
+
 type Admin struct {
 	ID        uuid.UUID
 	Email     string
@@ -16,28 +26,146 @@ type Admin struct {
 	DeletedAt pgtype.Timestamp
 }
 
+type ApiKey struct {
+	ID         uuid.UUID
+	EventID    uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	CreatedBy  string
+	CreatedAt  pgtype.Timestamp
+	ExpiresAt  pgtype.Timestamp
+	RevokedAt  pgtype.Timestamp
+	LastUsedAt pgtype.Timestamp
+}
+
+type AuditLog struct {
+	ID         uuid.UUID
+	ActorEmail string
+	ActorRole  string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     []byte
+	After      []byte
+	Ip         string
+	CreatedAt  pgtype.Timestamp
+}
+
 type Event struct {
+	ID                 uuid.UUID
+	Name               string
+	Place              string
+	Date               pgtype.Date
+	Host               string
+	AdminID            uuid.UUID
+	CreatedAt          pgtype.Timestamp
+	StartAt            pgtype.Timestamp
+	EndAt              pgtype.Timestamp
+	GraceBeforeMinutes int32
+	GraceAfterMinutes  int32
+	Status             string
+	Capacity           pgtype.Int4
+	RegistrationMode   string
+}
+
+type EventSession struct {
 	ID        uuid.UUID
+	EventID   uuid.UUID
 	Name      string
-	Place     string
-	Date      pgtype.Date
-	Host      string
-	AdminID   uuid.UUID
+	StartAt   pgtype.Timestamp
+	EndAt     pgtype.Timestamp
 	CreatedAt pgtype.Timestamp
 }
 
+type ExchangeCode struct {
+	CodeHash      string
+	CodeChallenge string
+	SessionID     string
+	Email         string
+	Name          string
+	Picture       string
+	Role          string
+	ExpiresAt     pgtype.Timestamp
+}
+
+type HttpSession struct {
+	ID        string
+	Data      []byte
+	ExpiresAt pgtype.Timestamp
+}
+
+type OauthState struct {
+	State     string
+	Provider  string
+	Binding   string
+	Verifier  string
+	Nonce     string
+	ExpiresAt pgtype.Timestamp
+}
+
 type Participant struct {
+	Barcode      string
+	Timestamp    pgtype.Timestamp
+	EventID      uuid.UUID
+	CheckedOutAt pgtype.Timestamp
+	StudentID    pgtype.Text
+	Campus       pgtype.Text
+	Faculty      pgtype.Text
+	EntryYear    pgtype.Int4
+	ScanID       uuid.UUID
+	ScannedBy    pgtype.Text
+	DeviceID     pgtype.Text
+	ApiKeyID     uuid.UUID
+}
+
+type RefreshToken struct {
+	ID         uuid.UUID
+	Email      string
+	TokenHash  string
+	UserAgent  string
+	Ip         string
+	CreatedAt  pgtype.Timestamp
+	LastUsedAt pgtype.Timestamp
+}
+
+type Registration struct {
+	EventID   uuid.UUID
+	StudentID string
+	CreatedBy string
+	CreatedAt pgtype.Timestamp
+}
+
+type SessionParticipant struct {
 	Barcode   string
 	Timestamp pgtype.Timestamp
-	EventID   uuid.UUID
+	SessionID uuid.UUID
 }
 
-type RefreshToken struct {
-	Email string
-	Token string
+type SigningKey struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey string
+	CreatedAt  pgtype.Timestamp
+	ExpiresAt  pgtype.Timestamp
+	ActiveFrom pgtype.Timestamp
 }
 
 type Staff struct {
 	Email   string
 	EventID uuid.UUID
+	Role    string
+}
+
+type Student struct {
+	StudentID string
+	FullName  string
+	Faculty   string
+	Major     string
+	UpdatedAt pgtype.Timestamp
+}
+
+type TokenVersion struct {
+	Email   string
+	Version int32
 }
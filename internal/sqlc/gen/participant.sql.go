@@ -12,27 +12,338 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const checkOutParticipant = `-- name: CheckOutParticipant :one
+UPDATE participants
+SET checked_out_at = $1
+WHERE event_id = $2 AND barcode = $3 AND checked_out_at IS NULL
+RETURNING barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id
+`
+
+type CheckOutParticipantParams struct {
+	CheckedOutAt pgtype.Timestamp
+	EventID      uuid.UUID
+	Barcode      string
+}
+
+func (q *Queries) CheckOutParticipant(ctx context.Context, arg CheckOutParticipantParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, checkOutParticipant, arg.CheckedOutAt, arg.EventID, arg.Barcode)
+	var i Participant
+	err := row.Scan(
+		&i.Barcode,
+		&i.Timestamp,
+		&i.EventID,
+		&i.CheckedOutAt,
+		&i.StudentID,
+		&i.Campus,
+		&i.Faculty,
+		&i.EntryYear,
+		&i.ScanID,
+		&i.ScannedBy,
+		&i.DeviceID,
+		&i.ApiKeyID,
+	)
+	return i, err
+}
+
+const countEventParticipants = `-- name: CountEventParticipants :one
+SELECT COUNT(*) FROM participants WHERE event_id = $1
+`
+
+func (q *Queries) CountEventParticipants(ctx context.Context, eventID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countEventParticipants, eventID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createParticipantRecord = `-- name: CreateParticipantRecord :one
-INSERT INTO participants (barcode,timestamp,event_id) VALUES ($1,$2,$3)
-RETURNING barcode, timestamp, event_id
+INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NULLIF($10::uuid, '00000000-0000-0000-0000-000000000000'))
+RETURNING barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id
 `
 
 type CreateParticipantRecordParams struct {
 	Barcode   string
 	Timestamp pgtype.Timestamp
 	EventID   uuid.UUID
+	StudentID pgtype.Text
+	Campus    pgtype.Text
+	Faculty   pgtype.Text
+	EntryYear pgtype.Int4
+	ScannedBy pgtype.Text
+	DeviceID  pgtype.Text
+	ApiKeyID  uuid.UUID
 }
 
 func (q *Queries) CreateParticipantRecord(ctx context.Context, arg CreateParticipantRecordParams) (Participant, error) {
-	row := q.db.QueryRow(ctx, createParticipantRecord, arg.Barcode, arg.Timestamp, arg.EventID)
+	row := q.db.QueryRow(ctx, createParticipantRecord,
+		arg.Barcode,
+		arg.Timestamp,
+		arg.EventID,
+		arg.StudentID,
+		arg.Campus,
+		arg.Faculty,
+		arg.EntryYear,
+		arg.ScannedBy,
+		arg.DeviceID,
+		arg.ApiKeyID,
+	)
+	var i Participant
+	err := row.Scan(
+		&i.Barcode,
+		&i.Timestamp,
+		&i.EventID,
+		&i.CheckedOutAt,
+		&i.StudentID,
+		&i.Campus,
+		&i.Faculty,
+		&i.EntryYear,
+		&i.ScanID,
+		&i.ScannedBy,
+		&i.DeviceID,
+		&i.ApiKeyID,
+	)
+	return i, err
+}
+
+const createParticipantRecordIfNotExists = `-- name: CreateParticipantRecordIfNotExists :one
+INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scan_id,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULLIF($11::uuid, '00000000-0000-0000-0000-000000000000'))
+ON CONFLICT DO NOTHING
+RETURNING barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id
+`
+
+type CreateParticipantRecordIfNotExistsParams struct {
+	Barcode   string
+	Timestamp pgtype.Timestamp
+	EventID   uuid.UUID
+	StudentID pgtype.Text
+	Campus    pgtype.Text
+	Faculty   pgtype.Text
+	EntryYear pgtype.Int4
+	ScanID    uuid.UUID
+	ScannedBy pgtype.Text
+	DeviceID  pgtype.Text
+	ApiKeyID  uuid.UUID
+}
+
+func (q *Queries) CreateParticipantRecordIfNotExists(ctx context.Context, arg CreateParticipantRecordIfNotExistsParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, createParticipantRecordIfNotExists,
+		arg.Barcode,
+		arg.Timestamp,
+		arg.EventID,
+		arg.StudentID,
+		arg.Campus,
+		arg.Faculty,
+		arg.EntryYear,
+		arg.ScanID,
+		arg.ScannedBy,
+		arg.DeviceID,
+		arg.ApiKeyID,
+	)
+	var i Participant
+	err := row.Scan(
+		&i.Barcode,
+		&i.Timestamp,
+		&i.EventID,
+		&i.CheckedOutAt,
+		&i.StudentID,
+		&i.Campus,
+		&i.Faculty,
+		&i.EntryYear,
+		&i.ScanID,
+		&i.ScannedBy,
+		&i.DeviceID,
+		&i.ApiKeyID,
+	)
+	return i, err
+}
+
+const createSessionParticipantRecord = `-- name: CreateSessionParticipantRecord :one
+WITH event_participant AS (
+	INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$9,$10,NULLIF($11::uuid, '00000000-0000-0000-0000-000000000000'))
+	ON CONFLICT DO NOTHING
+)
+INSERT INTO session_participants (barcode,timestamp,session_id) VALUES ($1,$2,$8)
+RETURNING barcode, timestamp, session_id
+`
+
+type CreateSessionParticipantRecordParams struct {
+	Barcode   string
+	Timestamp pgtype.Timestamp
+	EventID   uuid.UUID
+	StudentID pgtype.Text
+	Campus    pgtype.Text
+	Faculty   pgtype.Text
+	EntryYear pgtype.Int4
+	SessionID uuid.UUID
+	ScannedBy pgtype.Text
+	DeviceID  pgtype.Text
+	ApiKeyID  uuid.UUID
+}
+
+func (q *Queries) CreateSessionParticipantRecord(ctx context.Context, arg CreateSessionParticipantRecordParams) (SessionParticipant, error) {
+	row := q.db.QueryRow(ctx, createSessionParticipantRecord,
+		arg.Barcode,
+		arg.Timestamp,
+		arg.EventID,
+		arg.StudentID,
+		arg.Campus,
+		arg.Faculty,
+		arg.EntryYear,
+		arg.SessionID,
+		arg.ScannedBy,
+		arg.DeviceID,
+		arg.ApiKeyID,
+	)
+	var i SessionParticipant
+	err := row.Scan(&i.Barcode, &i.Timestamp, &i.SessionID)
+	return i, err
+}
+
+const getAttendanceSummaryByBarcode = `-- name: GetAttendanceSummaryByBarcode :one
+SELECT COUNT(*) AS total_events, COALESCE(SUM(EXTRACT(EPOCH FROM participants.checked_out_at - participants.timestamp)), 0)::BIGINT AS total_dwell_seconds
+FROM participants
+JOIN events ON events.id = participants.event_id
+WHERE participants.barcode = $1 AND events.date >= $2 AND events.date <= $3
+`
+
+type GetAttendanceSummaryByBarcodeParams struct {
+	Barcode string
+	Date    pgtype.Date
+	Date_2  pgtype.Date
+}
+
+type GetAttendanceSummaryByBarcodeRow struct {
+	TotalEvents       int64
+	TotalDwellSeconds int64
+}
+
+func (q *Queries) GetAttendanceSummaryByBarcode(ctx context.Context, arg GetAttendanceSummaryByBarcodeParams) (GetAttendanceSummaryByBarcodeRow, error) {
+	row := q.db.QueryRow(ctx, getAttendanceSummaryByBarcode, arg.Barcode, arg.Date, arg.Date_2)
+	var i GetAttendanceSummaryByBarcodeRow
+	err := row.Scan(&i.TotalEvents, &i.TotalDwellSeconds)
+	return i, err
+}
+
+const getAttendancesByBarcode = `-- name: GetAttendancesByBarcode :many
+SELECT participants.timestamp, participants.checked_out_at, events.id AS event_id, events.name AS event_name, events.place AS event_place, events.date AS event_date, events.host AS event_host
+FROM participants
+JOIN events ON events.id = participants.event_id
+WHERE participants.barcode = $1 AND events.date >= $2 AND events.date <= $3
+ORDER BY events.date DESC, participants.timestamp DESC
+LIMIT $4 OFFSET $5
+`
+
+type GetAttendancesByBarcodeParams struct {
+	Barcode string
+	Date    pgtype.Date
+	Date_2  pgtype.Date
+	Limit   int32
+	Offset  int32
+}
+
+type GetAttendancesByBarcodeRow struct {
+	Timestamp    pgtype.Timestamp
+	CheckedOutAt pgtype.Timestamp
+	EventID      uuid.UUID
+	EventName    string
+	EventPlace   string
+	EventDate    pgtype.Date
+	EventHost    string
+}
+
+func (q *Queries) GetAttendancesByBarcode(ctx context.Context, arg GetAttendancesByBarcodeParams) ([]GetAttendancesByBarcodeRow, error) {
+	rows, err := q.db.Query(ctx, getAttendancesByBarcode,
+		arg.Barcode,
+		arg.Date,
+		arg.Date_2,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAttendancesByBarcodeRow
+	for rows.Next() {
+		var i GetAttendancesByBarcodeRow
+		if err := rows.Scan(
+			&i.Timestamp,
+			&i.CheckedOutAt,
+			&i.EventID,
+			&i.EventName,
+			&i.EventPlace,
+			&i.EventDate,
+			&i.EventHost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getParticipantByBarcode = `-- name: GetParticipantByBarcode :one
+SELECT barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id FROM participants
+WHERE event_id = $1 AND barcode = $2
+`
+
+type GetParticipantByBarcodeParams struct {
+	EventID uuid.UUID
+	Barcode string
+}
+
+func (q *Queries) GetParticipantByBarcode(ctx context.Context, arg GetParticipantByBarcodeParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, getParticipantByBarcode, arg.EventID, arg.Barcode)
 	var i Participant
-	err := row.Scan(&i.Barcode, &i.Timestamp, &i.EventID)
+	err := row.Scan(
+		&i.Barcode,
+		&i.Timestamp,
+		&i.EventID,
+		&i.CheckedOutAt,
+		&i.StudentID,
+		&i.Campus,
+		&i.Faculty,
+		&i.EntryYear,
+		&i.ScanID,
+		&i.ScannedBy,
+		&i.DeviceID,
+		&i.ApiKeyID,
+	)
+	return i, err
+}
+
+const getParticipantByScanId = `-- name: GetParticipantByScanId :one
+SELECT barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id FROM participants
+WHERE scan_id = $1
+`
+
+func (q *Queries) GetParticipantByScanId(ctx context.Context, scanID uuid.UUID) (Participant, error) {
+	row := q.db.QueryRow(ctx, getParticipantByScanId, scanID)
+	var i Participant
+	err := row.Scan(
+		&i.Barcode,
+		&i.Timestamp,
+		&i.EventID,
+		&i.CheckedOutAt,
+		&i.StudentID,
+		&i.Campus,
+		&i.Faculty,
+		&i.EntryYear,
+		&i.ScanID,
+		&i.ScannedBy,
+		&i.DeviceID,
+		&i.ApiKeyID,
+	)
 	return i, err
 }
 
 const getParticipantCount = `-- name: GetParticipantCount :one
 SELECT COUNT(*) FROM participants
-WHERE event_id = $1 AND barcode LIKE $2
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.barcode LIKE $2 OR students.full_name ILIKE $2)
 `
 
 type GetParticipantCountParams struct {
@@ -48,9 +359,11 @@ func (q *Queries) GetParticipantCount(ctx context.Context, arg GetParticipantCou
 }
 
 const getParticipantPagination = `-- name: GetParticipantPagination :many
-SELECT barcode, timestamp, event_id FROM participants 
-WHERE event_id = $1 AND barcode LIKE $2
-ORDER BY timestamp DESC
+SELECT participants.barcode, participants.timestamp, participants.event_id, participants.checked_out_at, participants.student_id, participants.campus, participants.faculty, participants.entry_year, participants.scan_id, participants.scanned_by, participants.device_id, participants.api_key_id, students.full_name AS student_full_name, students.faculty AS student_faculty, students.major AS student_major
+FROM participants
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.barcode LIKE $2 OR students.full_name ILIKE $2)
+ORDER BY participants.timestamp DESC
 LIMIT $3 OFFSET $4
 `
 
@@ -61,7 +374,25 @@ type GetParticipantPaginationParams struct {
 	Offset  int32
 }
 
-func (q *Queries) GetParticipantPagination(ctx context.Context, arg GetParticipantPaginationParams) ([]Participant, error) {
+type GetParticipantPaginationRow struct {
+	Barcode         string
+	Timestamp       pgtype.Timestamp
+	EventID         uuid.UUID
+	CheckedOutAt    pgtype.Timestamp
+	StudentID       pgtype.Text
+	Campus          pgtype.Text
+	Faculty         pgtype.Text
+	EntryYear       pgtype.Int4
+	ScanID          uuid.UUID
+	ScannedBy       pgtype.Text
+	DeviceID        pgtype.Text
+	ApiKeyID        uuid.UUID
+	StudentFullName pgtype.Text
+	StudentFaculty  pgtype.Text
+	StudentMajor    pgtype.Text
+}
+
+func (q *Queries) GetParticipantPagination(ctx context.Context, arg GetParticipantPaginationParams) ([]GetParticipantPaginationRow, error) {
 	rows, err := q.db.Query(ctx, getParticipantPagination,
 		arg.EventID,
 		arg.Barcode,
@@ -72,10 +403,223 @@ func (q *Queries) GetParticipantPagination(ctx context.Context, arg GetParticipa
 		return nil, err
 	}
 	defer rows.Close()
+	var items []GetParticipantPaginationRow
+	for rows.Next() {
+		var i GetParticipantPaginationRow
+		if err := rows.Scan(
+			&i.Barcode,
+			&i.Timestamp,
+			&i.EventID,
+			&i.CheckedOutAt,
+			&i.StudentID,
+			&i.Campus,
+			&i.Faculty,
+			&i.EntryYear,
+			&i.ScanID,
+			&i.ScannedBy,
+			&i.DeviceID,
+			&i.ApiKeyID,
+			&i.StudentFullName,
+			&i.StudentFaculty,
+			&i.StudentMajor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getParticipantsAfter = `-- name: GetParticipantsAfter :many
+SELECT participants.barcode, participants.timestamp, participants.event_id, participants.checked_out_at, participants.student_id, participants.campus, participants.faculty, participants.entry_year, participants.scan_id, participants.scanned_by, participants.device_id, participants.api_key_id, students.full_name AS student_full_name, students.faculty AS student_faculty, students.major AS student_major
+FROM participants
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.timestamp > $2 OR (participants.timestamp = $2 AND participants.barcode > $3))
+ORDER BY participants.timestamp, participants.barcode
+LIMIT $4
+`
+
+type GetParticipantsAfterParams struct {
+	EventID   uuid.UUID
+	Timestamp pgtype.Timestamp
+	Barcode   string
+	Limit     int32
+}
+
+type GetParticipantsAfterRow struct {
+	Barcode         string
+	Timestamp       pgtype.Timestamp
+	EventID         uuid.UUID
+	CheckedOutAt    pgtype.Timestamp
+	StudentID       pgtype.Text
+	Campus          pgtype.Text
+	Faculty         pgtype.Text
+	EntryYear       pgtype.Int4
+	ScanID          uuid.UUID
+	ScannedBy       pgtype.Text
+	DeviceID        pgtype.Text
+	ApiKeyID        uuid.UUID
+	StudentFullName pgtype.Text
+	StudentFaculty  pgtype.Text
+	StudentMajor    pgtype.Text
+}
+
+func (q *Queries) GetParticipantsAfter(ctx context.Context, arg GetParticipantsAfterParams) ([]GetParticipantsAfterRow, error) {
+	rows, err := q.db.Query(ctx, getParticipantsAfter,
+		arg.EventID,
+		arg.Timestamp,
+		arg.Barcode,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetParticipantsAfterRow
+	for rows.Next() {
+		var i GetParticipantsAfterRow
+		if err := rows.Scan(
+			&i.Barcode,
+			&i.Timestamp,
+			&i.EventID,
+			&i.CheckedOutAt,
+			&i.StudentID,
+			&i.Campus,
+			&i.Faculty,
+			&i.EntryYear,
+			&i.ScanID,
+			&i.ScannedBy,
+			&i.DeviceID,
+			&i.ApiKeyID,
+			&i.StudentFullName,
+			&i.StudentFaculty,
+			&i.StudentMajor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getParticipantsByBarcodes = `-- name: GetParticipantsByBarcodes :many
+SELECT barcode, timestamp, event_id, checked_out_at, student_id, campus, faculty, entry_year, scan_id, scanned_by, device_id, api_key_id FROM participants
+WHERE event_id = $1 AND barcode = ANY($2::VARCHAR[])
+`
+
+type GetParticipantsByBarcodesParams struct {
+	EventID  uuid.UUID
+	Barcodes []string
+}
+
+func (q *Queries) GetParticipantsByBarcodes(ctx context.Context, arg GetParticipantsByBarcodesParams) ([]Participant, error) {
+	rows, err := q.db.Query(ctx, getParticipantsByBarcodes, arg.EventID, arg.Barcodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 	var items []Participant
 	for rows.Next() {
 		var i Participant
-		if err := rows.Scan(&i.Barcode, &i.Timestamp, &i.EventID); err != nil {
+		if err := rows.Scan(
+			&i.Barcode,
+			&i.Timestamp,
+			&i.EventID,
+			&i.CheckedOutAt,
+			&i.StudentID,
+			&i.Campus,
+			&i.Faculty,
+			&i.EntryYear,
+			&i.ScanID,
+			&i.ScannedBy,
+			&i.DeviceID,
+			&i.ApiKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStaffScanCounts = `-- name: GetStaffScanCounts :many
+SELECT COALESCE(scanned_by, '')::VARCHAR AS scanned_by, COUNT(*) AS scans, MIN(timestamp)::TIMESTAMP AS first_scan_at, MAX(timestamp)::TIMESTAMP AS last_scan_at
+FROM participants
+WHERE event_id = $1
+GROUP BY scanned_by
+ORDER BY scans DESC
+`
+
+type GetStaffScanCountsRow struct {
+	ScannedBy   string
+	Scans       int64
+	FirstScanAt pgtype.Timestamp
+	LastScanAt  pgtype.Timestamp
+}
+
+func (q *Queries) GetStaffScanCounts(ctx context.Context, eventID uuid.UUID) ([]GetStaffScanCountsRow, error) {
+	rows, err := q.db.Query(ctx, getStaffScanCounts, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStaffScanCountsRow
+	for rows.Next() {
+		var i GetStaffScanCountsRow
+		if err := rows.Scan(
+			&i.ScannedBy,
+			&i.Scans,
+			&i.FirstScanAt,
+			&i.LastScanAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStaffScanTimeline = `-- name: GetStaffScanTimeline :many
+SELECT COALESCE(scanned_by, '')::VARCHAR AS scanned_by, date_trunc($2, timestamp)::TIMESTAMP AS bucket, COUNT(*) AS scans
+FROM participants
+WHERE event_id = $1
+GROUP BY scanned_by, bucket
+ORDER BY bucket, scanned_by
+`
+
+type GetStaffScanTimelineParams struct {
+	EventID   uuid.UUID
+	DateTrunc string
+}
+
+type GetStaffScanTimelineRow struct {
+	ScannedBy string
+	Bucket    pgtype.Timestamp
+	Scans     int64
+}
+
+func (q *Queries) GetStaffScanTimeline(ctx context.Context, arg GetStaffScanTimelineParams) ([]GetStaffScanTimelineRow, error) {
+	rows, err := q.db.Query(ctx, getStaffScanTimeline, arg.EventID, arg.DateTrunc)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStaffScanTimelineRow
+	for rows.Next() {
+		var i GetStaffScanTimelineRow
+		if err := rows.Scan(&i.ScannedBy, &i.Bucket, &i.Scans); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -14,6 +14,7 @@ import (
 type CreateStaffsRecordParams struct {
 	Email   string
 	EventID uuid.UUID
+	Role    string
 }
 
 const deleteAllStaffFromEvent = `-- name: DeleteAllStaffFromEvent :exec
@@ -26,7 +27,7 @@ func (q *Queries) DeleteAllStaffFromEvent(ctx context.Context, eventID uuid.UUID
 }
 
 const getStaffByEventId = `-- name: GetStaffByEventId :many
-SELECT email, event_id FROM staffs WHERE event_id = $1
+SELECT email, event_id, role FROM staffs WHERE event_id = $1
 `
 
 func (q *Queries) GetStaffByEventId(ctx context.Context, eventID uuid.UUID) ([]Staff, error) {
@@ -38,7 +39,7 @@ func (q *Queries) GetStaffByEventId(ctx context.Context, eventID uuid.UUID) ([]S
 	var items []Staff
 	for rows.Next() {
 		var i Staff
-		if err := rows.Scan(&i.Email, &i.EventID); err != nil {
+		if err := rows.Scan(&i.Email, &i.EventID, &i.Role); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -50,7 +51,7 @@ func (q *Queries) GetStaffByEventId(ctx context.Context, eventID uuid.UUID) ([]S
 }
 
 const getStaffsByEmail = `-- name: GetStaffsByEmail :many
-SELECT email, event_id FROM staffs WHERE email = $1
+SELECT email, event_id, role FROM staffs WHERE email = $1
 `
 
 func (q *Queries) GetStaffsByEmail(ctx context.Context, email string) ([]Staff, error) {
@@ -62,7 +63,7 @@ func (q *Queries) GetStaffsByEmail(ctx context.Context, email string) ([]Staff,
 	var items []Staff
 	for rows.Next() {
 		var i Staff
-		if err := rows.Scan(&i.Email, &i.EventID); err != nil {
+		if err := rows.Scan(&i.Email, &i.EventID, &i.Role); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -74,7 +75,7 @@ func (q *Queries) GetStaffsByEmail(ctx context.Context, email string) ([]Staff,
 }
 
 const getStaffsByEmailAndEventId = `-- name: GetStaffsByEmailAndEventId :one
-SELECT email, event_id FROM staffs WHERE event_id = $1 AND email = $2
+SELECT email, event_id, role FROM staffs WHERE event_id = $1 AND email = $2
 `
 
 type GetStaffsByEmailAndEventIdParams struct {
@@ -85,6 +86,22 @@ type GetStaffsByEmailAndEventIdParams struct {
 func (q *Queries) GetStaffsByEmailAndEventId(ctx context.Context, arg GetStaffsByEmailAndEventIdParams) (Staff, error) {
 	row := q.db.QueryRow(ctx, getStaffsByEmailAndEventId, arg.EventID, arg.Email)
 	var i Staff
-	err := row.Scan(&i.Email, &i.EventID)
+	err := row.Scan(&i.Email, &i.EventID, &i.Role)
 	return i, err
 }
+
+const upsertStaff = `-- name: UpsertStaff :exec
+INSERT INTO staffs (email,event_id,role) VALUES ($1,$2,$3)
+ON CONFLICT (email,event_id) DO UPDATE SET role = EXCLUDED.role
+`
+
+type UpsertStaffParams struct {
+	Email   string
+	EventID uuid.UUID
+	Role    string
+}
+
+func (q *Queries) UpsertStaff(ctx context.Context, arg UpsertStaffParams) error {
+	_, err := q.db.Exec(ctx, upsertStaff, arg.Email, arg.EventID, arg.Role)
+	return err
+}
@@ -7,38 +7,154 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
+const bumpTokenVersion = `-- name: BumpTokenVersion :exec
+INSERT INTO token_versions (email, version) VALUES ($1, 1)
+ON CONFLICT (email) DO UPDATE SET version = token_versions.version + 1
+`
+
+func (q *Queries) BumpTokenVersion(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, bumpTokenVersion, email)
+	return err
+}
+
 const createRefreshToken = `-- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens (token, email) VALUES ($1, $2)
+INSERT INTO refresh_tokens (id, email, token_hash, user_agent, ip) VALUES ($1, $2, $3, $4, $5)
 `
 
 type CreateRefreshTokenParams struct {
-	Token string
-	Email string
+	ID        uuid.UUID
+	Email     string
+	TokenHash string
+	UserAgent string
+	Ip        string
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
-	_, err := q.db.Exec(ctx, createRefreshToken, arg.Token, arg.Email)
+	_, err := q.db.Exec(ctx, createRefreshToken,
+		arg.ID,
+		arg.Email,
+		arg.TokenHash,
+		arg.UserAgent,
+		arg.Ip,
+	)
 	return err
 }
 
-const deleteRefreshToken = `-- name: DeleteRefreshToken :exec
+const deleteRefreshToken = `-- name: DeleteRefreshToken :execrows
+DELETE FROM refresh_tokens WHERE id = $1 AND email = $2
+`
+
+type DeleteRefreshTokenParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) DeleteRefreshToken(ctx context.Context, arg DeleteRefreshTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRefreshToken, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRefreshTokensByEmail = `-- name: DeleteRefreshTokensByEmail :exec
 DELETE FROM refresh_tokens WHERE email = $1
 `
 
-func (q *Queries) DeleteRefreshToken(ctx context.Context, email string) error {
-	_, err := q.db.Exec(ctx, deleteRefreshToken, email)
+func (q *Queries) DeleteRefreshTokensByEmail(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, deleteRefreshTokensByEmail, email)
 	return err
 }
 
 const getRefreshToken = `-- name: GetRefreshToken :one
-SELECT email, token FROM refresh_tokens WHERE email = $1
+SELECT id, email, token_hash, user_agent, ip, created_at, last_used_at FROM refresh_tokens WHERE id = $1
 `
 
-func (q *Queries) GetRefreshToken(ctx context.Context, email string) (RefreshToken, error) {
-	row := q.db.QueryRow(ctx, getRefreshToken, email)
+func (q *Queries) GetRefreshToken(ctx context.Context, id uuid.UUID) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshToken, id)
 	var i RefreshToken
-	err := row.Scan(&i.Email, &i.Token)
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.TokenHash,
+		&i.UserAgent,
+		&i.Ip,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
 	return i, err
 }
+
+const getRefreshTokensByEmail = `-- name: GetRefreshTokensByEmail :many
+SELECT id, email, token_hash, user_agent, ip, created_at, last_used_at FROM refresh_tokens WHERE email = $1 ORDER BY last_used_at DESC
+`
+
+func (q *Queries) GetRefreshTokensByEmail(ctx context.Context, email string) ([]RefreshToken, error) {
+	rows, err := q.db.Query(ctx, getRefreshTokensByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefreshToken
+	for rows.Next() {
+		var i RefreshToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.TokenHash,
+			&i.UserAgent,
+			&i.Ip,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTokenVersion = `-- name: GetTokenVersion :one
+SELECT version FROM token_versions WHERE email = $1
+`
+
+func (q *Queries) GetTokenVersion(ctx context.Context, email string) (int32, error) {
+	row := q.db.QueryRow(ctx, getTokenVersion, email)
+	var version int32
+	err := row.Scan(&version)
+	return version, err
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :execrows
+UPDATE refresh_tokens SET token_hash = $1, user_agent = $2, ip = $3, last_used_at = CURRENT_TIMESTAMP
+WHERE id = $4 AND token_hash = $5
+`
+
+type RotateRefreshTokenParams struct {
+	TokenHash   string
+	UserAgent   string
+	Ip          string
+	ID          uuid.UUID
+	TokenHash_2 string
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateRefreshToken,
+		arg.TokenHash,
+		arg.UserAgent,
+		arg.Ip,
+		arg.ID,
+		arg.TokenHash_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,20 @@
+-- name: CreateAccessRequest :exec
+INSERT INTO access_requests (email,full_name) VALUES ($1,$2)
+ON CONFLICT (email) WHERE status = 'pending' DO NOTHING;
+
+-- name: GetAccessRequestById :one
+SELECT * FROM access_requests WHERE id = $1;
+
+-- name: GetAccessRequestPagination :many
+SELECT * FROM access_requests
+WHERE status LIKE $1 AND email LIKE $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountAccessRequests :one
+SELECT COUNT(*) FROM access_requests
+WHERE status LIKE $1 AND email LIKE $2;
+
+-- name: ReviewAccessRequest :execrows
+UPDATE access_requests SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = $3 AND status = 'pending';
@@ -0,0 +1,15 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys (event_id,name,prefix,key_hash,created_by,expires_at) VALUES ($1,$2,$3,$4,$5,$6)
+RETURNING *;
+
+-- name: GetApiKeyByHash :one
+SELECT * FROM api_keys WHERE key_hash = $1;
+
+-- name: GetApiKeysByEventId :many
+SELECT * FROM api_keys WHERE event_id = $1 ORDER BY created_at DESC;
+
+-- name: RevokeApiKey :execrows
+UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND event_id = $3 AND revoked_at IS NULL;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys SET last_used_at = $1 WHERE id = $2;
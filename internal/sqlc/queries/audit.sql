@@ -0,0 +1,14 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (actor_email,actor_role,action,target_type,target_id,before,after,ip) VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+
+-- name: GetAuditLogPagination :many
+SELECT * FROM audit_logs
+WHERE actor_email LIKE $1 AND action LIKE $2 AND target_type LIKE $3 AND target_id LIKE $4
+AND created_at >= $5 AND created_at < $6
+ORDER BY created_at DESC
+LIMIT $7 OFFSET $8;
+
+-- name: CountAuditLogs :one
+SELECT COUNT(*) FROM audit_logs
+WHERE actor_email LIKE $1 AND action LIKE $2 AND target_type LIKE $3 AND target_id LIKE $4
+AND created_at >= $5 AND created_at < $6;
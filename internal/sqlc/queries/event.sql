@@ -1,26 +1,44 @@
 -- name: GetAllEvents :many
 SELECT * FROM events
 INNER JOIN admins ON events.admin_id = admins.id
-WHERE events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1
+WHERE (events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1) AND events.status LIKE $2
 ORDER BY (events.date,events.created_at) DESC
-LIMIT $2 OFFSET $3;
+LIMIT $3 OFFSET $4;
 
 -- name: GetEventCount :one
 SELECT COUNT(*) FROM events
-WHERE events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1;
+WHERE (events.name LIKE $1 OR events.place LIKE $1 OR events.host LIKE $1) AND events.status LIKE $2;
 
 -- name: GetEventById :one
 SELECT * FROM events
 INNER JOIN admins ON events.admin_id = admins.id
 WHERE events.id = $1;
 
--- name: CreateEvent :exec
-INSERT INTO events (name,place,date,host,admin_id) VALUES ($1,$2,$3,$4,$5);
+-- name: CreateEvent :one
+INSERT INTO events (name,place,date,host,admin_id,start_at,end_at,grace_before_minutes,grace_after_minutes,capacity) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+RETURNING id;
 
 -- name: DeleteEventById :exec
 DELETE FROM events WHERE id = $1;
 
 -- name: UpdateEventById :exec
 UPDATE events
-SET name = $1, place = $2, date = $3, host = $4
-WHERE id = $5;
+SET name = $1, place = $2, date = $3, host = $4, start_at = $5, end_at = $6, grace_before_minutes = $7, grace_after_minutes = $8
+WHERE id = $9;
+
+-- name: UpdateEventStatus :execrows
+UPDATE events SET status = $1 WHERE id = $2 AND status = $3;
+
+-- name: UpdateEventCapacity :execrows
+UPDATE events SET capacity = $1 WHERE id = $2;
+
+-- name: GetEventCapacity :one
+SELECT events.capacity, (SELECT COUNT(*) FROM participants WHERE participants.event_id = events.id) AS admitted
+FROM events
+WHERE events.id = $1;
+
+-- name: LockEventCapacity :one
+SELECT capacity FROM events WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateEventRegistrationMode :execrows
+UPDATE events SET registration_mode = $1 WHERE id = $2;
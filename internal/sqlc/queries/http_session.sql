@@ -0,0 +1,15 @@
+-- name: GetHttpSession :one
+SELECT data FROM http_sessions WHERE id = $1 AND (expires_at IS NULL OR expires_at > $2);
+
+-- name: UpsertHttpSession :exec
+INSERT INTO http_sessions (id, data, expires_at) VALUES ($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at;
+
+-- name: DeleteHttpSession :exec
+DELETE FROM http_sessions WHERE id = $1;
+
+-- name: DeleteHttpSessions :exec
+DELETE FROM http_sessions;
+
+-- name: DeleteExpiredHttpSessions :exec
+DELETE FROM http_sessions WHERE expires_at <= $1;
@@ -0,0 +1,18 @@
+-- name: CreateOAuthState :exec
+INSERT INTO oauth_states (state, provider, binding, verifier, nonce, expires_at) VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: TakeOAuthState :one
+DELETE FROM oauth_states WHERE state = $1 RETURNING *;
+
+-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states WHERE expires_at <= $1;
+
+-- name: CreateExchangeCode :exec
+INSERT INTO exchange_codes (code_hash, code_challenge, session_id, email, name, picture, role, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: TakeExchangeCode :one
+DELETE FROM exchange_codes WHERE code_hash = $1 RETURNING *;
+
+-- name: DeleteExpiredExchangeCodes :exec
+DELETE FROM exchange_codes WHERE expires_at <= $1;
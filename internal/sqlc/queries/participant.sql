@@ -1,17 +1,94 @@
 -- name: CreateParticipantRecord :one
-INSERT INTO participants (barcode,timestamp,event_id) VALUES ($1,$2,$3)
+INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NULLIF(sqlc.arg(api_key_id)::uuid, '00000000-0000-0000-0000-000000000000'))
+RETURNING *;
+
+-- name: CreateParticipantRecordIfNotExists :one
+INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scan_id,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULLIF(sqlc.arg(api_key_id)::uuid, '00000000-0000-0000-0000-000000000000'))
+ON CONFLICT DO NOTHING
 RETURNING *;
 
 -- name: GetParticipantPagination :many
-SELECT * FROM participants 
-WHERE event_id = $1 AND barcode LIKE $2
-ORDER BY timestamp DESC
+SELECT participants.*, students.full_name AS student_full_name, students.faculty AS student_faculty, students.major AS student_major
+FROM participants
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.barcode LIKE $2 OR students.full_name ILIKE $2)
+ORDER BY participants.timestamp DESC
 LIMIT $3 OFFSET $4;
 
 -- name: GetParticipantCount :one
 SELECT COUNT(*) FROM participants
-WHERE event_id = $1 AND barcode LIKE $2;
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.barcode LIKE $2 OR students.full_name ILIKE $2);
 
 -- name: DeleteParticipantsByBarcode :batchexec
+WITH deleted_session_participants AS (
+	DELETE FROM session_participants
+	WHERE session_participants.barcode = $1
+	AND session_participants.session_id IN (SELECT event_sessions.id FROM event_sessions WHERE event_sessions.event_id = $2)
+)
 DELETE FROM participants WHERE barcode = $1 AND event_id = $2;
 
+-- name: GetParticipantByBarcode :one
+SELECT * FROM participants
+WHERE event_id = $1 AND barcode = $2;
+
+-- name: GetParticipantByScanId :one
+SELECT * FROM participants
+WHERE scan_id = $1;
+
+-- name: CheckOutParticipant :one
+UPDATE participants
+SET checked_out_at = $1
+WHERE event_id = $2 AND barcode = $3 AND checked_out_at IS NULL
+RETURNING *;
+
+-- name: CreateSessionParticipantRecord :one
+WITH event_participant AS (
+	INSERT INTO participants (barcode,timestamp,event_id,student_id,campus,faculty,entry_year,scanned_by,device_id,api_key_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$9,$10,NULLIF(sqlc.arg(api_key_id)::uuid, '00000000-0000-0000-0000-000000000000'))
+	ON CONFLICT DO NOTHING
+)
+INSERT INTO session_participants (barcode,timestamp,session_id) VALUES ($1,$2,$8)
+RETURNING *;
+
+-- name: GetParticipantsAfter :many
+SELECT participants.*, students.full_name AS student_full_name, students.faculty AS student_faculty, students.major AS student_major
+FROM participants
+LEFT JOIN students ON students.student_id = participants.student_id
+WHERE participants.event_id = $1 AND (participants.timestamp > $2 OR (participants.timestamp = $2 AND participants.barcode > $3))
+ORDER BY participants.timestamp, participants.barcode
+LIMIT $4;
+
+-- name: GetParticipantsByBarcodes :many
+SELECT * FROM participants
+WHERE event_id = $1 AND barcode = ANY(sqlc.arg(barcodes)::VARCHAR[]);
+
+-- name: GetAttendancesByBarcode :many
+SELECT participants.timestamp, participants.checked_out_at, events.id AS event_id, events.name AS event_name, events.place AS event_place, events.date AS event_date, events.host AS event_host
+FROM participants
+JOIN events ON events.id = participants.event_id
+WHERE participants.barcode = $1 AND events.date >= $2 AND events.date <= $3
+ORDER BY events.date DESC, participants.timestamp DESC
+LIMIT $4 OFFSET $5;
+
+-- name: GetAttendanceSummaryByBarcode :one
+SELECT COUNT(*) AS total_events, COALESCE(SUM(EXTRACT(EPOCH FROM participants.checked_out_at - participants.timestamp)), 0)::BIGINT AS total_dwell_seconds
+FROM participants
+JOIN events ON events.id = participants.event_id
+WHERE participants.barcode = $1 AND events.date >= $2 AND events.date <= $3;
+
+-- name: GetStaffScanCounts :many
+SELECT COALESCE(scanned_by, '')::VARCHAR AS scanned_by, COUNT(*) AS scans, MIN(timestamp)::TIMESTAMP AS first_scan_at, MAX(timestamp)::TIMESTAMP AS last_scan_at
+FROM participants
+WHERE event_id = $1
+GROUP BY scanned_by
+ORDER BY scans DESC;
+
+-- name: GetStaffScanTimeline :many
+SELECT COALESCE(scanned_by, '')::VARCHAR AS scanned_by, date_trunc($2, timestamp)::TIMESTAMP AS bucket, COUNT(*) AS scans
+FROM participants
+WHERE event_id = $1
+GROUP BY scanned_by, bucket
+ORDER BY bucket, scanned_by;
+
+-- name: CountEventParticipants :one
+SELECT COUNT(*) FROM participants WHERE event_id = $1;
@@ -0,0 +1,35 @@
+-- name: CreateRegistrations :execrows
+INSERT INTO registrations (event_id,student_id,created_by)
+SELECT sqlc.arg(event_id)::UUID, unnest(sqlc.arg(student_ids)::VARCHAR[]), sqlc.arg(created_by)::VARCHAR
+ON CONFLICT DO NOTHING;
+
+-- name: GetRegistrationsPagination :many
+SELECT registrations.*, students.full_name AS student_full_name,
+EXISTS (SELECT 1 FROM participants WHERE participants.event_id = registrations.event_id AND participants.student_id = registrations.student_id) AS attended
+FROM registrations
+LEFT JOIN students ON students.student_id = registrations.student_id
+WHERE registrations.event_id = $1 AND (registrations.student_id LIKE $2 OR students.full_name ILIKE $2)
+ORDER BY registrations.student_id
+LIMIT $3 OFFSET $4;
+
+-- name: GetRegistrationCount :one
+SELECT COUNT(*) FROM registrations
+LEFT JOIN students ON students.student_id = registrations.student_id
+WHERE registrations.event_id = $1 AND (registrations.student_id LIKE $2 OR students.full_name ILIKE $2);
+
+-- name: GetRegisteredStudentIds :many
+SELECT student_id FROM registrations
+WHERE event_id = $1 AND student_id = ANY(sqlc.arg(student_ids)::VARCHAR[]);
+
+-- name: DeleteRegistration :execrows
+DELETE FROM registrations WHERE event_id = $1 AND student_id = $2;
+
+-- name: GetRegistrationSummary :one
+SELECT
+	(SELECT COUNT(*) FROM registrations WHERE registrations.event_id = $1) AS registered,
+	(SELECT COUNT(*) FROM participants
+		JOIN registrations ON registrations.event_id = participants.event_id AND registrations.student_id = participants.student_id
+		WHERE participants.event_id = $1) AS attended,
+	(SELECT COUNT(*) FROM participants
+		WHERE participants.event_id = $1
+		AND NOT EXISTS (SELECT 1 FROM registrations WHERE registrations.event_id = participants.event_id AND registrations.student_id = participants.student_id)) AS walk_ins;
@@ -0,0 +1,40 @@
+-- name: GetSessionsByEventId :many
+SELECT event_sessions.id, event_sessions.event_id, event_sessions.name, event_sessions.start_at, event_sessions.end_at, COUNT(session_participants.barcode) AS participants_count
+FROM event_sessions
+LEFT JOIN session_participants ON session_participants.session_id = event_sessions.id
+WHERE event_sessions.event_id = $1
+GROUP BY event_sessions.id
+ORDER BY event_sessions.start_at;
+
+-- name: GetSessionById :one
+SELECT * FROM event_sessions WHERE id = $1;
+
+-- name: CountSessionsByEventId :one
+SELECT COUNT(*) FROM event_sessions WHERE event_id = $1;
+
+-- name: CreateSession :one
+INSERT INTO event_sessions (event_id,name,start_at,end_at) VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: UpdateSessionById :exec
+UPDATE event_sessions
+SET name = $1, start_at = $2, end_at = $3
+WHERE id = $4;
+
+-- name: DeleteSessionById :exec
+DELETE FROM event_sessions WHERE id = $1;
+
+-- name: GetSessionAttendancePagination :many
+SELECT session_participants.barcode, COUNT(*) AS sessions_attended
+FROM session_participants
+INNER JOIN event_sessions ON session_participants.session_id = event_sessions.id
+WHERE event_sessions.event_id = $1 AND session_participants.barcode LIKE $2
+GROUP BY session_participants.barcode
+ORDER BY session_participants.barcode
+LIMIT $3 OFFSET $4;
+
+-- name: CountSessionAttendance :one
+SELECT COUNT(DISTINCT session_participants.barcode)
+FROM session_participants
+INNER JOIN event_sessions ON session_participants.session_id = event_sessions.id
+WHERE event_sessions.event_id = $1 AND session_participants.barcode LIKE $2;
@@ -0,0 +1,8 @@
+-- name: CreateSigningKey :exec
+INSERT INTO signing_keys (kid,algorithm,private_key,created_at,expires_at,active_from) VALUES ($1,$2,$3,$4,$5,$6);
+
+-- name: GetActiveSigningKeys :many
+SELECT * FROM signing_keys WHERE expires_at > $1 ORDER BY active_from DESC;
+
+-- name: DeleteExpiredSigningKeys :exec
+DELETE FROM signing_keys WHERE expires_at <= $1;
@@ -1,5 +1,5 @@
 -- name: CreateStaffsRecord :copyfrom
-INSERT INTO staffs (email,event_id) VALUES ($1,$2);
+INSERT INTO staffs (email,event_id,role) VALUES ($1,$2,$3);
 
 -- name: DeleteAllStaffFromEvent :exec
 DELETE FROM staffs WHERE event_id = $1;
@@ -12,3 +12,7 @@ SELECT * FROM staffs WHERE email = $1;
 
 -- name: GetStaffsByEmailAndEventId :one
 SELECT * FROM staffs WHERE event_id = $1 AND email = $2;
+
+-- name: UpsertStaff :exec
+INSERT INTO staffs (email,event_id,role) VALUES ($1,$2,$3)
+ON CONFLICT (email,event_id) DO UPDATE SET role = EXCLUDED.role;
@@ -0,0 +1,17 @@
+-- name: UpsertStudents :batchexec
+INSERT INTO students (student_id,full_name,faculty,major) VALUES ($1,$2,$3,$4)
+ON CONFLICT (student_id) DO UPDATE
+SET full_name = EXCLUDED.full_name, faculty = EXCLUDED.faculty, major = EXCLUDED.major, updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetStudentsPagination :many
+SELECT * FROM students
+WHERE student_id LIKE $1 OR full_name ILIKE $1
+ORDER BY student_id
+LIMIT $2 OFFSET $3;
+
+-- name: CountStudents :one
+SELECT COUNT(*) FROM students
+WHERE student_id LIKE $1 OR full_name ILIKE $1;
+
+-- name: GetStudentById :one
+SELECT * FROM students WHERE student_id = $1;
@@ -1,8 +1,25 @@
 -- name: GetRefreshToken :one
-SELECT * FROM refresh_tokens WHERE email = $1;
+SELECT * FROM refresh_tokens WHERE id = $1;
+
+-- name: GetRefreshTokensByEmail :many
+SELECT * FROM refresh_tokens WHERE email = $1 ORDER BY last_used_at DESC;
 
 -- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens (token, email) VALUES ($1, $2);
+INSERT INTO refresh_tokens (id, email, token_hash, user_agent, ip) VALUES ($1, $2, $3, $4, $5);
+
+-- name: RotateRefreshToken :execrows
+UPDATE refresh_tokens SET token_hash = $1, user_agent = $2, ip = $3, last_used_at = CURRENT_TIMESTAMP
+WHERE id = $4 AND token_hash = $5;
+
+-- name: DeleteRefreshToken :execrows
+DELETE FROM refresh_tokens WHERE id = $1 AND email = $2;
 
--- name: DeleteRefreshToken :exec
+-- name: DeleteRefreshTokensByEmail :exec
 DELETE FROM refresh_tokens WHERE email = $1;
+
+-- name: GetTokenVersion :one
+SELECT version FROM token_versions WHERE email = $1;
+
+-- name: BumpTokenVersion :exec
+INSERT INTO token_versions (email, version) VALUES ($1, 1)
+ON CONFLICT (email) DO UPDATE SET version = token_versions.version + 1;
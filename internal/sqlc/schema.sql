@@ -13,14 +13,24 @@ CREATE TABLE events (
 	host VARCHAR(255) NOT NULL,
 	admin_id UUID,
 	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	start_at TIMESTAMP,
+	end_at TIMESTAMP,
+	grace_before_minutes INTEGER NOT NULL DEFAULT 0,
+	grace_after_minutes INTEGER NOT NULL DEFAULT 0,
+	status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'open', 'closed', 'archived')),
+	capacity INTEGER CHECK (capacity > 0),
+	registration_mode VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (registration_mode IN ('open', 'registered_only', 'warn_unregistered')),
 
 	UNIQUE(name,place,date),
 	FOREIGN KEY(admin_id) REFERENCES admins(id) ON DELETE CASCADE
 );
 
+CREATE INDEX events_status_idx ON events (status);
+
 CREATE TABLE staffs (
 	email VARCHAR(255) NOT NULL,
 	event_id UUID,
+	role VARCHAR(20) NOT NULL DEFAULT 'supervisor' CHECK (role IN ('scanner', 'supervisor', 'co_organiser')),
 
 	UNIQUE (email, event_id),
 	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
@@ -30,12 +40,153 @@ CREATE TABLE participants (
 	barcode VARCHAR(14) NOT NULL,
 	timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	event_id UUID,
+	checked_out_at TIMESTAMP,
+	student_id VARCHAR(10),
+	campus VARCHAR(1),
+	faculty VARCHAR(2),
+	entry_year INTEGER,
+	scan_id UUID UNIQUE,
+	scanned_by VARCHAR(255),
+	device_id VARCHAR(100),
+	api_key_id UUID,
 
 	PRIMARY KEY(barcode,event_id),
 	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
 );
 
+CREATE TABLE event_sessions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	event_id UUID NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	start_at TIMESTAMP NOT NULL,
+	end_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE session_participants (
+	barcode VARCHAR(14) NOT NULL,
+	timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	session_id UUID NOT NULL,
+
+	PRIMARY KEY(barcode,session_id),
+	FOREIGN KEY(session_id) REFERENCES event_sessions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE students (
+	student_id VARCHAR(10) PRIMARY KEY,
+	full_name VARCHAR(255) NOT NULL,
+	faculty VARCHAR(255) NOT NULL,
+	major VARCHAR(255) NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE refresh_tokens (
+	id UUID PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	token_hash VARCHAR(64) NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip VARCHAR(45) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refresh_tokens_email_idx ON refresh_tokens (email);
+
+CREATE TABLE audit_logs (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	actor_email VARCHAR(255) NOT NULL,
+	actor_role VARCHAR(20) NOT NULL,
+	action VARCHAR(100) NOT NULL,
+	target_type VARCHAR(50) NOT NULL,
+	target_id VARCHAR(255) NOT NULL,
+	before JSONB,
+	after JSONB,
+	ip VARCHAR(45) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_logs_created_at_idx ON audit_logs (created_at);
+CREATE INDEX audit_logs_target_idx ON audit_logs (target_type, target_id);
+
+CREATE TABLE access_requests (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	email VARCHAR(255) NOT NULL,
+	full_name VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+	reviewed_by VARCHAR(255),
+	reviewed_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX access_requests_pending_email_idx ON access_requests (email) WHERE status = 'pending';
+
+CREATE TABLE signing_keys (
+	kid VARCHAR(64) PRIMARY KEY,
+	algorithm VARCHAR(10) NOT NULL,
+	private_key TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	expires_at TIMESTAMP NOT NULL,
+	active_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE token_versions (
 	email VARCHAR(255) PRIMARY KEY,
-	token VARCHAR(255) NOT NULL
+	version INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE api_keys (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	event_id UUID NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	prefix VARCHAR(12) NOT NULL,
+	key_hash VARCHAR(64) NOT NULL UNIQUE,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP,
+	last_used_at TIMESTAMP,
+
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX api_keys_event_id_idx ON api_keys (event_id);
+
+ALTER TABLE participants ADD FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE SET NULL;
+
+CREATE TABLE registrations (
+	event_id UUID NOT NULL,
+	student_id VARCHAR(10) NOT NULL,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY(event_id, student_id),
+	FOREIGN KEY(event_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE oauth_states (
+	state VARCHAR(64) PRIMARY KEY,
+	provider VARCHAR(64) NOT NULL,
+	binding VARCHAR(64) NOT NULL,
+	verifier VARCHAR(128) NOT NULL,
+	nonce VARCHAR(64) NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE exchange_codes (
+	code_hash VARCHAR(64) PRIMARY KEY,
+	code_challenge VARCHAR(128) NOT NULL,
+	session_id VARCHAR(64) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	picture TEXT NOT NULL,
+	role VARCHAR(20) NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE http_sessions (
+	id VARCHAR(64) PRIMARY KEY,
+	data BYTEA NOT NULL,
+	expires_at TIMESTAMP
 );
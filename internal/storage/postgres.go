@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	sqlc "github.com/SornchaiTheDev/nisit-scan-backend/internal/sqlc/gen"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const gcInterval = 10 * time.Minute
+
+type postgresStorage struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	q      *sqlc.Queries
+}
+
+// NewPostgresStorage keeps fiber sessions in the http_sessions table, so a
+// login started on one instance can finish on another. Expired sessions are
+// deleted in the background until ctx is done or the storage is closed.
+func NewPostgresStorage(ctx context.Context, q *sqlc.Queries) fiber.Storage {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &postgresStorage{
+		ctx:    ctx,
+		cancel: cancel,
+		q:      q,
+	}
+
+	go s.gc()
+
+	return s
+}
+
+func (s *postgresStorage) gc() {
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.q.DeleteExpiredHttpSessions(s.ctx, pgtype.Timestamp{Time: time.Now(), Valid: true})
+			if err != nil && s.ctx.Err() == nil {
+				log.Println("Cannot delete expired sessions: ", err)
+			}
+		}
+	}
+}
+
+func (s *postgresStorage) Get(key string) ([]byte, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	data, err := s.q.GetHttpSession(s.ctx, sqlc.GetHttpSessionParams{
+		ID:        key,
+		ExpiresAt: pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *postgresStorage) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+
+	expiresAt := pgtype.Timestamp{}
+	if exp > 0 {
+		expiresAt = pgtype.Timestamp{Time: time.Now().Add(exp), Valid: true}
+	}
+
+	return s.q.UpsertHttpSession(s.ctx, sqlc.UpsertHttpSessionParams{
+		ID:        key,
+		Data:      val,
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *postgresStorage) Delete(key string) error {
+	if key == "" {
+		return nil
+	}
+
+	return s.q.DeleteHttpSession(s.ctx, key)
+}
+
+func (s *postgresStorage) Reset() error {
+	return s.q.DeleteHttpSessions(s.ctx)
+}
+
+func (s *postgresStorage) Close() error {
+	s.cancel()
+	return nil
+}